@@ -0,0 +1,2 @@
+#include "testlib.h"
+int main(int argc, char* argv[]) { registerTestlibCmd(argc, argv); quitf(_ok, "ok"); }
@@ -0,0 +1 @@
+int n = inf.readInt(1, 10, "n");
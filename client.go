@@ -1,13 +1,20 @@
 package polygon
 
 import (
+	"archive/zip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
+	"net"
 	"net/http"
 	"net/url"
+	"path"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 )
 
@@ -16,10 +23,15 @@ type httpClient interface {
 }
 
 type Client struct {
-	key    string
-	secret string
-	base   string
-	cli    httpClient
+	key     string
+	secret  string
+	base    string
+	cli     httpClient
+	retries int           // number of retries for transient failures
+	delay   time.Duration // initial delay between retries
+	limiter *limiter      // optional request throttling
+	now     func() time.Time
+	salt    func() string
 }
 
 func New(key, secret string, opts ...func(*Client)) *Client {
@@ -28,6 +40,9 @@ func New(key, secret string, opts ...func(*Client)) *Client {
 		secret: secret,
 		base:   "https://polygon.codeforces.com/api/",
 		cli:    http.DefaultClient,
+		delay:  time.Second,
+		now:    time.Now,
+		salt:   Salt,
 	}
 
 	for _, opt := range opts {
@@ -38,6 +53,35 @@ func New(key, secret string, opts ...func(*Client)) *Client {
 }
 
 func (c *Client) request(ctx context.Context, method string, params map[string]string) (*http.Response, error) {
+	return c.send(ctx, http.MethodGet, method, params, nil)
+}
+
+// send signs parameters and sends them to the API, GET requests carry parameters in query string and POST requests
+// carry them in form encoded body (used to upload sources and tests which do not fit into query string).
+//
+// GET requests failed due to network errors, server errors or rate limiting are retried according to the retry
+// settings. POST requests change the problem and are never retried, because failed request might have been applied
+// (e.g. problem is created, but response is lost), and repeating it would create duplicates.
+func (c *Client) send(ctx context.Context, verb, method string, params map[string]string, header http.Header) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.attempt(ctx, verb, method, params, header)
+		if err == nil || verb != http.MethodGet || attempt >= c.retries || ctx.Err() != nil || !transient(err) {
+			return resp, err
+		}
+
+		timer := time.NewTimer(c.backoff(attempt))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends a single HTTP request, each attempt is signed separately because signature depends on time
+func (c *Client) attempt(ctx context.Context, verb, method string, params map[string]string, header http.Header) (*http.Response, error) {
 	base, err := url.Parse(c.base)
 	if err != nil {
 		return nil, fmt.Errorf("base URL %#v is corrupted: %w", c.base, err)
@@ -48,30 +92,120 @@ func (c *Client) request(ctx context.Context, method string, params map[string]s
 		query.Set(k, v)
 	}
 
-	query.Set("time", fmt.Sprint(time.Now().Unix()))
+	query.Set("time", fmt.Sprint(c.now().Unix()))
 	query.Set("apiKey", c.key)
-	query.Set("apiSig", Signature(method, c.secret, query))
+	query.Set("apiSig", sign(c.salt(), method, c.secret, query))
 
 	base.Path = strings.TrimSuffix(base.Path, "/") + "/" + url.PathEscape(method)
 
-	req, err := http.NewRequest(http.MethodGet, base.String()+"?"+query.Encode(), nil)
+	var req *http.Request
+	if verb == http.MethodPost {
+		req, err = http.NewRequest(http.MethodPost, base.String(), strings.NewReader(query.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		req, err = http.NewRequest(verb, base.String()+"?"+query.Encode(), nil)
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("unable to compose HTTP request: %w", err)
 	}
 
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	release := func() {}
+	if c.limiter != nil {
+		if release, err = c.limiter.acquire(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	resp, err := c.cli.Do(req.WithContext(ctx))
 	if err != nil {
+		release()
 		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		_ = resp.Body.Close()
-		return nil, fmt.Errorf("response status code is not OK: %v", resp.Status)
+	resp.Body = &limitedBody{ReadCloser: resp.Body, release: release}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		defer resp.Body.Close()
+
+		// polygon describes errors in the envelop, but proxies and load balancers might respond with anything
+		envelop := &Envelop{}
+		if err := json.NewDecoder(resp.Body).Decode(envelop); err != nil || envelop.Status == "" {
+			return nil, &APIError{Method: method, Status: resp.Status, Code: resp.StatusCode}
+		}
+
+		return nil, &APIError{Method: method, Status: envelop.Status, Comment: envelop.Comment, Code: resp.StatusCode}
 	}
 
 	return resp, nil
 }
 
+// newDownload describes streamed archive using Content-Length and Content-Range headers of the response
+func newDownload(resp *http.Response) *Download {
+	download := &Download{ReadCloser: resp.Body, Size: resp.ContentLength}
+	if resp.StatusCode != http.StatusPartialContent {
+		return download
+	}
+
+	// Content-Range: bytes 100-999/1000, total size can be replaced by an asterisk
+	var first, last int64
+	var total string
+	if _, err := fmt.Sscanf(resp.Header.Get("Content-Range"), "bytes %d-%d/%s", &first, &last, &total); err != nil {
+		return download
+	}
+
+	download.Offset = first
+	download.Size = -1
+
+	if size, err := strconv.ParseInt(total, 10, 64); err == nil {
+		download.Size = size
+	}
+
+	return download
+}
+
+// transient returns true if error is caused by network or transient server failure. Note, every error returned by
+// http.Client is a net.Error, but only timeouts are transient, e.g. TLS verification or unsupported scheme errors are not.
+func transient(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.transient()
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// backoff returns exponential delay with jitter before the next attempt
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := min(c.delay<<attempt, 30*time.Second)
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// host returns host (and port) of the API the client is configured for
+func (c *Client) host() string {
+	base, err := url.Parse(c.base)
+	if err != nil {
+		return ""
+	}
+
+	return base.Host
+}
+
 func (c *Client) call(ctx context.Context, method string, params map[string]string) (*Envelop, error) {
 	resp, err := c.request(ctx, method, params)
 	if err != nil {
@@ -80,7 +214,25 @@ func (c *Client) call(ctx context.Context, method string, params map[string]stri
 
 	defer resp.Body.Close()
 
-	envelop := &Envelop{}
+	envelop := &Envelop{method: method, code: resp.StatusCode}
+
+	if err := json.NewDecoder(resp.Body).Decode(envelop); err != nil {
+		return nil, err
+	}
+
+	return envelop, nil
+}
+
+// submit is similar to call, but uses POST request, it's used by methods which modify problem
+func (c *Client) submit(ctx context.Context, method string, params map[string]string) (*Envelop, error) {
+	resp, err := c.send(ctx, http.MethodPost, method, params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	envelop := &Envelop{method: method, code: resp.StatusCode}
 
 	if err := json.NewDecoder(resp.Body).Decode(envelop); err != nil {
 		return nil, err
@@ -104,11 +256,188 @@ func (c *Client) ListPackages(ctx context.Context, in ListPackagesInput) ([]Pack
 	return packages, nil
 }
 
-func (c *Client) DownloadPackage(ctx context.Context, in DownloadPackageInput) (io.ReadCloser, error) {
-	resp, err := c.request(ctx, "problem.package", map[string]string{
+// DownloadPackage streams package archive, set Offset to resume interrupted download.
+func (c *Client) DownloadPackage(ctx context.Context, in DownloadPackageInput) (*Download, error) {
+	var header http.Header
+	if in.Offset > 0 {
+		header = http.Header{"Range": {fmt.Sprintf("bytes=%d-", in.Offset)}}
+	}
+
+	resp, err := c.send(ctx, http.MethodGet, "problem.package", map[string]string{
 		"problemId": fmt.Sprint(in.ProblemID),
 		"packageId": fmt.Sprint(in.PackageID),
 		"type":      in.Type,
+	}, header)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newDownload(resp), nil
+}
+
+func (c *Client) ProblemInfo(ctx context.Context, in ProblemInfoInput) (*ProblemInfo, error) {
+	env, err := c.call(ctx, "problem.info", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ProblemInfo{}
+
+	if err := env.Unmarshal(info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func (c *Client) ViewTags(ctx context.Context, in ViewTagsInput) ([]string, error) {
+	env, err := c.call(ctx, "problem.viewTags", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+
+	if err := env.Unmarshal(&tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (c *Client) ViewGeneralDescription(ctx context.Context, in ViewGeneralDescriptionInput) (string, error) {
+	env, err := c.call(ctx, "problem.viewGeneralDescription", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return "", err
+	}
+
+	var description string
+
+	if err := env.Unmarshal(&description); err != nil {
+		return "", err
+	}
+
+	return description, nil
+}
+
+func (c *Client) ViewGeneralTutorial(ctx context.Context, in ViewGeneralTutorialInput) (string, error) {
+	env, err := c.call(ctx, "problem.viewGeneralTutorial", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return "", err
+	}
+
+	var tutorial string
+
+	if err := env.Unmarshal(&tutorial); err != nil {
+		return "", err
+	}
+
+	return tutorial, nil
+}
+
+// Statements returns problem statements, keyed by statement language.
+func (c *Client) Statements(ctx context.Context, in StatementsInput) (map[string]Statement, error) {
+	env, err := c.call(ctx, "problem.statements", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return nil, err
+	}
+
+	statements := map[string]Statement{}
+
+	if err := env.Unmarshal(&statements); err != nil {
+		return nil, err
+	}
+
+	return statements, nil
+}
+
+// StatementResources returns files attached to the statements (images and so on), use StatementResource to stream
+// content of a single resource.
+func (c *Client) StatementResources(ctx context.Context, in StatementResourcesInput) ([]File, error) {
+	env, err := c.call(ctx, "problem.statementResources", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+
+	if err := env.Unmarshal(&files); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// StatementResource streams a single statement resource from the existing package, caller must close returned reader.
+// Polygon API has no method to view statement resources, so the resource is taken from the package archive. The
+// archive is not downloaded as a whole: only its central directory and the resource are requested using HTTP ranges.
+func (c *Client) StatementResource(ctx context.Context, in StatementResourceInput) (io.ReadCloser, error) {
+	archive, err := c.newPackageReader(ctx, DownloadPackageInput{ProblemID: in.ProblemID, PackageID: in.PackageID, Type: in.Type})
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := zip.NewReader(archive, archive.size)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read package archive: %w", err)
+	}
+
+	return reader.Open(path.Join("statements", in.Language, in.Name))
+}
+
+// ViewFile streams content of a problem file, caller must close returned reader.
+func (c *Client) ViewFile(ctx context.Context, in ViewFileInput) (io.ReadCloser, error) {
+	resp, err := c.request(ctx, "problem.viewFile", map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"type":      in.Type,
+		"name":      in.Name,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// Files returns resource, source and auxiliary files of the problem.
+func (c *Client) Files(ctx context.Context, in FilesInput) (*Files, error) {
+	env, err := c.call(ctx, "problem.files", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return nil, err
+	}
+
+	files := &Files{}
+
+	if err := env.Unmarshal(files); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// Solutions returns list of problem solutions with their tags.
+func (c *Client) Solutions(ctx context.Context, in SolutionsInput) ([]Solution, error) {
+	env, err := c.call(ctx, "problem.solutions", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return nil, err
+	}
+
+	var solutions []Solution
+
+	if err := env.Unmarshal(&solutions); err != nil {
+		return nil, err
+	}
+
+	return solutions, nil
+}
+
+// ViewSolution streams solution source code, caller must close returned reader.
+func (c *Client) ViewSolution(ctx context.Context, in ViewSolutionInput) (io.ReadCloser, error) {
+	resp, err := c.request(ctx, "problem.viewSolution", map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"name":      in.Name,
 	})
 
 	if err != nil {
@@ -117,3 +446,528 @@ func (c *Client) DownloadPackage(ctx context.Context, in DownloadPackageInput) (
 
 	return resp.Body, nil
 }
+
+// Script returns generation script for the testset.
+func (c *Client) Script(ctx context.Context, in ScriptInput) (string, error) {
+	resp, err := c.request(ctx, "problem.script", map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"testset":   in.Testset,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("unable to read script: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// Tests returns tests of the testset.
+func (c *Client) Tests(ctx context.Context, in TestsInput) ([]Test, error) {
+	params := map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"testset":   in.Testset,
+	}
+
+	if in.NoInputs {
+		params["noInputs"] = "true"
+	}
+
+	env, err := c.call(ctx, "problem.tests", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var tests []Test
+
+	if err := env.Unmarshal(&tests); err != nil {
+		return nil, err
+	}
+
+	return tests, nil
+}
+
+// TestInput streams test input, caller must close returned reader.
+func (c *Client) TestInput(ctx context.Context, in TestInputInput) (io.ReadCloser, error) {
+	resp, err := c.request(ctx, "problem.testInput", map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"testset":   in.Testset,
+		"testIndex": fmt.Sprint(in.TestIndex),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// TestAnswer streams test answer, caller must close returned reader.
+func (c *Client) TestAnswer(ctx context.Context, in TestAnswerInput) (io.ReadCloser, error) {
+	resp, err := c.request(ctx, "problem.testAnswer", map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"testset":   in.Testset,
+		"testIndex": fmt.Sprint(in.TestIndex),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// ViewTestGroup returns test groups of the testset.
+func (c *Client) ViewTestGroup(ctx context.Context, in ViewTestGroupInput) ([]TestGroup, error) {
+	params := map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"testset":   in.Testset,
+	}
+
+	if in.Group != "" {
+		params["group"] = in.Group
+	}
+
+	env, err := c.call(ctx, "problem.viewTestGroup", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []TestGroup
+
+	if err := env.Unmarshal(&groups); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// CreateProblem creates an empty problem.
+func (c *Client) CreateProblem(ctx context.Context, in CreateProblemInput) (*Problem, error) {
+	env, err := c.submit(ctx, "problem.create", map[string]string{"name": in.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	problem := &Problem{}
+
+	if err := env.Unmarshal(problem); err != nil {
+		return nil, err
+	}
+
+	return problem, nil
+}
+
+// UpdateInfo updates problem info, only populated fields are updated.
+func (c *Client) UpdateInfo(ctx context.Context, in UpdateInfoInput) error {
+	params := map[string]string{"problemId": fmt.Sprint(in.ProblemID)}
+
+	if in.InputFile != "" {
+		params["inputFile"] = in.InputFile
+	}
+
+	if in.OutputFile != "" {
+		params["outputFile"] = in.OutputFile
+	}
+
+	if in.Interactive != nil {
+		params["interactive"] = fmt.Sprint(*in.Interactive)
+	}
+
+	if in.TimeLimit != 0 {
+		params["timeLimit"] = fmt.Sprint(in.TimeLimit)
+	}
+
+	if in.MemoryLimit != 0 {
+		params["memoryLimit"] = fmt.Sprint(in.MemoryLimit)
+	}
+
+	env, err := c.submit(ctx, "problem.updateInfo", params)
+	if err != nil {
+		return err
+	}
+
+	return env.Check()
+}
+
+// SaveStatement creates or updates statement in the given language, empty statement sections are not updated.
+func (c *Client) SaveStatement(ctx context.Context, in SaveStatementInput) error {
+	params := map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"lang":      in.Lang,
+	}
+
+	for name, value := range map[string]string{
+		"encoding":    in.Statement.Encoding,
+		"name":        in.Statement.Name,
+		"legend":      in.Statement.Legend,
+		"input":       in.Statement.Input,
+		"output":      in.Statement.Output,
+		"scoring":     in.Statement.Scoring,
+		"interaction": in.Statement.Interaction,
+		"notes":       in.Statement.Notes,
+		"tutorial":    in.Statement.Tutorial,
+	} {
+		if value != "" {
+			params[name] = value
+		}
+	}
+
+	env, err := c.submit(ctx, "problem.saveStatement", params)
+	if err != nil {
+		return err
+	}
+
+	return env.Check()
+}
+
+// SaveFile adds or updates resource, source or auxiliary file.
+func (c *Client) SaveFile(ctx context.Context, in SaveFileInput) error {
+	params := map[string]string{
+		"problemId":     fmt.Sprint(in.ProblemID),
+		"checkExisting": fmt.Sprint(in.CheckExisting),
+		"type":          in.Type,
+		"name":          in.Name,
+		"file":          string(in.Data),
+	}
+
+	if in.SourceType != "" {
+		params["sourceType"] = in.SourceType
+	}
+
+	if in.ForTypes != "" {
+		params["forTypes"] = in.ForTypes
+	}
+
+	if len(in.Stages) > 0 {
+		params["stages"] = strings.Join(in.Stages, ";")
+	}
+
+	if len(in.Assets) > 0 {
+		params["assets"] = strings.Join(in.Assets, ";")
+	}
+
+	env, err := c.submit(ctx, "problem.saveFile", params)
+	if err != nil {
+		return err
+	}
+
+	return env.Check()
+}
+
+// SaveSolution adds or updates solution.
+func (c *Client) SaveSolution(ctx context.Context, in SaveSolutionInput) error {
+	params := map[string]string{
+		"problemId":     fmt.Sprint(in.ProblemID),
+		"checkExisting": fmt.Sprint(in.CheckExisting),
+		"name":          in.Name,
+		"file":          string(in.Data),
+		"tag":           in.Tag,
+	}
+
+	if in.SourceType != "" {
+		params["sourceType"] = in.SourceType
+	}
+
+	env, err := c.submit(ctx, "problem.saveSolution", params)
+	if err != nil {
+		return err
+	}
+
+	return env.Check()
+}
+
+// SaveScript updates generation script for the testset.
+func (c *Client) SaveScript(ctx context.Context, in SaveScriptInput) error {
+	env, err := c.submit(ctx, "problem.saveScript", map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"testset":   in.Testset,
+		"source":    in.Source,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return env.Check()
+}
+
+// SaveTest adds or updates manual test.
+func (c *Client) SaveTest(ctx context.Context, in SaveTestInput) error {
+	params := map[string]string{
+		"problemId":     fmt.Sprint(in.ProblemID),
+		"checkExisting": fmt.Sprint(in.CheckExisting),
+		"testset":       in.Testset,
+		"testIndex":     fmt.Sprint(in.TestIndex),
+		"testInput":     string(in.TestInput),
+	}
+
+	if in.TestGroup != "" {
+		params["testGroup"] = in.TestGroup
+	}
+
+	if in.TestPoints != nil {
+		params["testPoints"] = fmt.Sprint(*in.TestPoints)
+	}
+
+	if in.TestDescription != "" {
+		params["testDescription"] = in.TestDescription
+	}
+
+	if in.TestUseInStatements != nil {
+		params["testUseInStatements"] = fmt.Sprint(*in.TestUseInStatements)
+	}
+
+	if in.TestInputForStatements != "" {
+		params["testInputForStatements"] = in.TestInputForStatements
+	}
+
+	if in.TestOutputForStatements != "" {
+		params["testOutputForStatements"] = in.TestOutputForStatements
+	}
+
+	if in.VerifyInputOutputForStatements != nil {
+		params["verifyInputOutputForStatements"] = fmt.Sprint(*in.VerifyInputOutputForStatements)
+	}
+
+	env, err := c.submit(ctx, "problem.saveTest", params)
+	if err != nil {
+		return err
+	}
+
+	return env.Check()
+}
+
+// SaveTags replaces problem tags.
+func (c *Client) SaveTags(ctx context.Context, in SaveTagsInput) error {
+	env, err := c.submit(ctx, "problem.saveTags", map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"tags":      strings.Join(in.Tags, ","),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return env.Check()
+}
+
+// EnableGroups enables or disables test groups for the testset.
+func (c *Client) EnableGroups(ctx context.Context, in EnableGroupsInput) error {
+	env, err := c.submit(ctx, "problem.enableGroups", map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"testset":   in.Testset,
+		"enable":    fmt.Sprint(in.Enable),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return env.Check()
+}
+
+// EnablePoints enables or disables test points for the problem.
+func (c *Client) EnablePoints(ctx context.Context, in EnablePointsInput) error {
+	env, err := c.submit(ctx, "problem.enablePoints", map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"enable":    fmt.Sprint(in.Enable),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return env.Check()
+}
+
+// SaveTestGroup updates test group settings.
+func (c *Client) SaveTestGroup(ctx context.Context, in SaveTestGroupInput) error {
+	params := map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"testset":   in.Testset,
+		"group":     in.Group,
+	}
+
+	if in.PointsPolicy != "" {
+		params["pointsPolicy"] = in.PointsPolicy
+	}
+
+	if in.FeedbackPolicy != "" {
+		params["feedbackPolicy"] = in.FeedbackPolicy
+	}
+
+	if len(in.Dependencies) > 0 {
+		params["dependencies"] = strings.Join(in.Dependencies, ",")
+	}
+
+	env, err := c.submit(ctx, "problem.saveTestGroup", params)
+	if err != nil {
+		return err
+	}
+
+	return env.Check()
+}
+
+// CommitChanges commits problem changes made in the working copy.
+func (c *Client) CommitChanges(ctx context.Context, in CommitChangesInput) error {
+	params := map[string]string{
+		"problemId":    fmt.Sprint(in.ProblemID),
+		"minorChanges": fmt.Sprint(in.MinorChanges),
+	}
+
+	if in.Message != "" {
+		params["message"] = in.Message
+	}
+
+	env, err := c.submit(ctx, "problem.commitChanges", params)
+	if err != nil {
+		return err
+	}
+
+	return env.Check()
+}
+
+// ListProblems returns problems available to the user, use ID filter to get current revision of a single problem.
+func (c *Client) ListProblems(ctx context.Context, in ListProblemsInput) ([]Problem, error) {
+	params := map[string]string{}
+
+	if in.ShowDeleted {
+		params["showDeleted"] = "true"
+	}
+
+	if in.ID != 0 {
+		params["id"] = fmt.Sprint(in.ID)
+	}
+
+	if in.Name != "" {
+		params["name"] = in.Name
+	}
+
+	if in.Owner != "" {
+		params["owner"] = in.Owner
+	}
+
+	env, err := c.call(ctx, "problems.list", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+
+	if err := env.Unmarshal(&problems); err != nil {
+		return nil, err
+	}
+
+	return problems, nil
+}
+
+// BuildPackage starts building a new package, use WaitPackage to wait until it's built.
+func (c *Client) BuildPackage(ctx context.Context, in BuildPackageInput) error {
+	env, err := c.submit(ctx, "problem.buildPackage", map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"full":      fmt.Sprint(in.Full),
+		"verify":    fmt.Sprint(in.Verify),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return env.Check()
+}
+
+// WaitPackage polls the list of packages until the latest package created after the given one (or the package with the
+// given ID) is READY or FAILED.
+func (c *Client) WaitPackage(ctx context.Context, in WaitPackageInput) (*Package, error) {
+	interval := in.Interval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	for {
+		packages, err := c.ListPackages(ctx, ListPackagesInput{ProblemID: in.ProblemID})
+		if err != nil {
+			return nil, err
+		}
+
+		var latest *Package
+		for _, pack := range packages {
+			if (in.ID != 0 && pack.ID == in.ID) || (in.ID == 0 && pack.ID > in.After && (latest == nil || pack.ID > latest.ID)) {
+				latest = &pack
+			}
+		}
+
+		switch {
+		case latest != nil && latest.State == "READY":
+			return latest, nil
+		case latest != nil && latest.State == "FAILED":
+			return nil, fmt.Errorf("package #%v build has failed: %v", latest.ID, latest.Comment)
+		}
+
+		timer := time.NewTimer(interval)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		interval = min(interval*2, time.Minute)
+	}
+}
+
+// ContestProblems returns problems of the contest, keyed by problem letter.
+func (c *Client) ContestProblems(ctx context.Context, in ContestProblemsInput) (map[string]Problem, error) {
+	env, err := c.call(ctx, "contest.problems", map[string]string{"contestId": fmt.Sprint(in.ContestID)})
+	if err != nil {
+		return nil, err
+	}
+
+	problems := map[string]Problem{}
+
+	if err := env.Unmarshal(&problems); err != nil {
+		return nil, err
+	}
+
+	return problems, nil
+}
+
+// CheckerTests returns tests used to verify checker.
+func (c *Client) CheckerTests(ctx context.Context, in CheckerTestsInput) ([]CheckerTest, error) {
+	env, err := c.call(ctx, "problem.checkerTests", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return nil, err
+	}
+
+	var tests []CheckerTest
+
+	if err := env.Unmarshal(&tests); err != nil {
+		return nil, err
+	}
+
+	return tests, nil
+}
+
+// ValidatorTests returns tests used to verify validator.
+func (c *Client) ValidatorTests(ctx context.Context, in ValidatorTestsInput) ([]ValidatorTest, error) {
+	env, err := c.call(ctx, "problem.validatorTests", map[string]string{"problemId": fmt.Sprint(in.ProblemID)})
+	if err != nil {
+		return nil, err
+	}
+
+	var tests []ValidatorTest
+
+	if err := env.Unmarshal(&tests); err != nil {
+		return nil, err
+	}
+
+	return tests, nil
+}
@@ -1,5 +1,10 @@
 package polygon
 
+import (
+	"io"
+	"time"
+)
+
 type ListPackagesInput struct {
 	ProblemID int
 }
@@ -8,6 +13,7 @@ type DownloadPackageInput struct {
 	ProblemID int
 	PackageID int
 	Type      string
+	Offset    int64 // request content starting from this byte, server might ignore it, see Download.Offset
 }
 
 type Package struct {
@@ -18,3 +24,312 @@ type Package struct {
 	Comment             string `json:"comment"`             // comment for the package
 	Type                string `json:"type"`                // type of the package: standard/linux/windows
 }
+
+// Download is a streamed package archive.
+type Download struct {
+	io.ReadCloser
+	Offset int64 // position of the first streamed byte in the archive, 0 if server ignored requested offset
+	Size   int64 // size of the whole archive in bytes, -1 if unknown
+}
+
+type ProblemInfoInput struct {
+	ProblemID int
+}
+
+type ProblemInfo struct {
+	InputFile   string `json:"inputFile"`   // problem's input file
+	OutputFile  string `json:"outputFile"`  // problem's output file
+	Interactive bool   `json:"interactive"` // is problem interactive
+	TimeLimit   int    `json:"timeLimit"`   // problem's time limit in milliseconds
+	MemoryLimit int    `json:"memoryLimit"` // problem's memory limit in MB
+}
+
+type ViewTagsInput struct {
+	ProblemID int
+}
+
+type ViewGeneralDescriptionInput struct {
+	ProblemID int
+}
+
+type ViewGeneralTutorialInput struct {
+	ProblemID int
+}
+
+type StatementsInput struct {
+	ProblemID int
+}
+
+type Statement struct {
+	Encoding    string `json:"encoding"`    // statement's encoding
+	Name        string `json:"name"`        // problem's name in statement's language
+	Legend      string `json:"legend"`      // problem's legend
+	Input       string `json:"input"`       // problem's input format
+	Output      string `json:"output"`      // problem's output format
+	Scoring     string `json:"scoring"`     // problem's scoring
+	Interaction string `json:"interaction"` // problem's interaction protocol (only for interactive problems)
+	Notes       string `json:"notes"`       // statement notes
+	Tutorial    string `json:"tutorial"`    // problem's tutorial
+}
+
+type StatementResourcesInput struct {
+	ProblemID int
+}
+
+type StatementResourceInput struct {
+	ProblemID int
+	PackageID int    // package to take resource from, see ListPackages
+	Type      string // package type: standard/linux/windows
+	Language  string // statement language, e.g. english
+	Name      string // resource name as returned by StatementResources
+}
+
+type File struct {
+	Name                       string                      `json:"name"`                                 // file's name
+	ModificationTimeSeconds    int                         `json:"modificationTimeSeconds"`              // file's modification time in unix format
+	Length                     int                         `json:"length"`                               // file's length
+	SourceType                 string                      `json:"sourceType,omitempty"`                 // source file type (only for source files)
+	ResourceAdvancedProperties *ResourceAdvancedProperties `json:"resourceAdvancedProperties,omitempty"` // resource file properties (only for resource files)
+}
+
+type ResourceAdvancedProperties struct {
+	ForTypes string   `json:"forTypes"` // comma-separated list of language types this resource is used for
+	Main     bool     `json:"main"`     // currently reserved to be false
+	Stages   []string `json:"stages"`   // COMPILE or RUN, stages at which resource is used
+	Assets   []string `json:"assets"`   // VALIDATOR, INTERACTOR, CHECKER, SOLUTION, assets resource is used with
+}
+
+type ViewFileInput struct {
+	ProblemID int
+	Type      string // resource/source/aux
+	Name      string
+}
+
+type FilesInput struct {
+	ProblemID int
+}
+
+type Files struct {
+	ResourceFiles []File `json:"resourceFiles"` // resource files (testlib.h, olymp.sty and so on)
+	SourceFiles   []File `json:"sourceFiles"`   // source files (checker, validator, generators and so on)
+	AuxFiles      []File `json:"auxFiles"`      // auxiliary files
+}
+
+type SolutionsInput struct {
+	ProblemID int
+}
+
+type Solution struct {
+	Name                    string `json:"name"`                    // solution's name
+	ModificationTimeSeconds int    `json:"modificationTimeSeconds"` // solution's modification time in unix format
+	Length                  int    `json:"length"`                  // solution's length
+	SourceType              string `json:"sourceType"`              // solution's source type
+	Tag                     string `json:"tag"`                     // solution's tag: MA, OK, RJ, TL, TO, WA, PE, ML or RE
+}
+
+type ViewSolutionInput struct {
+	ProblemID int
+	Name      string
+}
+
+type ScriptInput struct {
+	ProblemID int
+	Testset   string
+}
+
+type TestsInput struct {
+	ProblemID int
+	Testset   string
+	NoInputs  bool // do not include input of manual tests into response
+}
+
+type Test struct {
+	Index                          int     `json:"index"`                                    // test index
+	Manual                         bool    `json:"manual"`                                   // whether test is manual or generated
+	Input                          string  `json:"input,omitempty"`                          // test input (only for manual tests)
+	Description                    string  `json:"description,omitempty"`                    // test description
+	UseInStatements                bool    `json:"useInStatements"`                          // whether test is included in statements
+	ScriptLine                     string  `json:"scriptLine,omitempty"`                     // script line for generating test (only for generated tests)
+	Group                          string  `json:"group,omitempty"`                          // test group (only if groups are enabled)
+	Points                         float32 `json:"points,omitempty"`                         // test points (only if points are enabled)
+	InputForStatement              string  `json:"inputForStatement,omitempty"`              // input to be shown in statements (if differs from input)
+	OutputForStatement             string  `json:"outputForStatement,omitempty"`             // output to be shown in statements (if differs from answer)
+	VerifyInputOutputForStatements bool    `json:"verifyInputOutputForStatements,omitempty"` // whether statement input and output should be verified
+}
+
+type TestInputInput struct {
+	ProblemID int
+	Testset   string
+	TestIndex int
+}
+
+type TestAnswerInput struct {
+	ProblemID int
+	Testset   string
+	TestIndex int
+}
+
+type ViewTestGroupInput struct {
+	ProblemID int
+	Testset   string
+	Group     string // group name, all groups are returned if empty
+}
+
+type TestGroup struct {
+	Name           string   `json:"name"`           // group name
+	PointsPolicy   string   `json:"pointsPolicy"`   // COMPLETE_GROUP or EACH_TEST
+	FeedbackPolicy string   `json:"feedbackPolicy"` // NONE, POINTS, ICPC or COMPLETE
+	Dependencies   []string `json:"dependencies"`   // names of the groups this group depends on
+}
+
+type Problem struct {
+	ID            int    `json:"id"`                      // problem's id
+	Owner         string `json:"owner"`                   // problem owner's handle
+	Name          string `json:"name"`                    // problem's name
+	Deleted       bool   `json:"deleted"`                 // is problem deleted
+	Favourite     bool   `json:"favourite"`               // is problem in user's favourites
+	AccessType    string `json:"accessType"`              // user's access type for the problem: READ/WRITE/OWNER
+	Revision      int    `json:"revision"`                // current problem revision
+	LatestPackage int    `json:"latestPackage,omitempty"` // latest revision with package available
+	Modified      bool   `json:"modified"`                // is problem modified (has uncommitted changes)
+}
+
+type CreateProblemInput struct {
+	Name string
+}
+
+type UpdateInfoInput struct {
+	ProblemID   int
+	InputFile   string // not updated if empty
+	OutputFile  string // not updated if empty
+	Interactive *bool  // not updated if nil
+	TimeLimit   int    // in milliseconds, not updated if zero
+	MemoryLimit int    // in MB, not updated if zero
+}
+
+type SaveStatementInput struct {
+	ProblemID int
+	Lang      string
+	Statement Statement // empty fields are not updated
+}
+
+type SaveFileInput struct {
+	ProblemID     int
+	CheckExisting bool   // if true, only adding files is allowed
+	Type          string // resource/source/aux
+	Name          string
+	Data          []byte
+	SourceType    string   // optional, only for source files
+	ForTypes      string   // optional, only for resource files
+	Stages        []string // optional, only for resource files: COMPILE or RUN
+	Assets        []string // optional, only for resource files: VALIDATOR, INTERACTOR, CHECKER, SOLUTION
+}
+
+type SaveSolutionInput struct {
+	ProblemID     int
+	CheckExisting bool // if true, only adding solutions is allowed
+	Name          string
+	Data          []byte
+	SourceType    string // optional
+	Tag           string // MA, OK, RJ, TL, TO, WA, PE, ML or RE
+}
+
+type SaveScriptInput struct {
+	ProblemID int
+	Testset   string
+	Source    string
+}
+
+type SaveTestInput struct {
+	ProblemID                      int
+	CheckExisting                  bool // if true, only adding tests is allowed
+	Testset                        string
+	TestIndex                      int
+	TestInput                      []byte
+	TestGroup                      string   // optional, only if groups are enabled
+	TestPoints                     *float32 // optional, only if points are enabled
+	TestDescription                string   // optional
+	TestUseInStatements            *bool    // optional
+	TestInputForStatements         string   // optional
+	TestOutputForStatements        string   // optional
+	VerifyInputOutputForStatements *bool    // optional
+}
+
+type SaveTagsInput struct {
+	ProblemID int
+	Tags      []string
+}
+
+type EnableGroupsInput struct {
+	ProblemID int
+	Testset   string
+	Enable    bool
+}
+
+type EnablePointsInput struct {
+	ProblemID int
+	Enable    bool
+}
+
+type SaveTestGroupInput struct {
+	ProblemID      int
+	Testset        string
+	Group          string
+	PointsPolicy   string   // optional: COMPLETE_GROUP or EACH_TEST
+	FeedbackPolicy string   // optional: NONE, POINTS, ICPC or COMPLETE
+	Dependencies   []string // optional
+}
+
+type CommitChangesInput struct {
+	ProblemID    int
+	MinorChanges bool // if true, no email notification will be sent
+	Message      string
+}
+
+type ListProblemsInput struct {
+	ShowDeleted bool   // show deleted problems, false by default
+	ID          int    // optional, problem id
+	Name        string // optional, problem name
+	Owner       string // optional, problem owner login
+}
+
+type BuildPackageInput struct {
+	ProblemID int
+	Full      bool // build full package (with generated tests, required for windows and linux packages)
+	Verify    bool // run all solutions on all tests
+}
+
+type WaitPackageInput struct {
+	ProblemID int
+	After     int           // wait for package with ID greater than this one
+	ID        int           // wait for this exact package (e.g. one which is already being built), After is ignored
+	Interval  time.Duration // initial polling interval, doubled after each attempt up to a minute
+}
+
+type ContestProblemsInput struct {
+	ContestID int
+}
+
+type CheckerTestsInput struct {
+	ProblemID int
+}
+
+type CheckerTest struct {
+	Index           int    `json:"index"`           // test index
+	Input           string `json:"input"`           // test input
+	Output          string `json:"output"`          // participant's output
+	Answer          string `json:"answer"`          // jury answer
+	ExpectedVerdict string `json:"expectedVerdict"` // OK, WRONG_ANSWER, PRESENTATION_ERROR or CRASHED
+}
+
+type ValidatorTestsInput struct {
+	ProblemID int
+}
+
+type ValidatorTest struct {
+	Index           int    `json:"index"`             // test index
+	Input           string `json:"input"`             // test input
+	ExpectedVerdict string `json:"expectedVerdict"`   // VALID or INVALID
+	Testset         string `json:"testset,omitempty"` // testset the test is validated for
+	Group           string `json:"group,omitempty"`   // group the test is validated for
+}
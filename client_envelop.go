@@ -3,18 +3,29 @@ package polygon
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 )
 
 type Envelop struct {
 	Status  string           `json:"status"`
 	Comment string           `json:"comment"`
 	Result  *json.RawMessage `json:"result"`
+
+	method string // API method, used to compose an error
+	code   int    // HTTP status code, used to compose an error
 }
 
-func (e *Envelop) Unmarshal(v any) error {
+// Check returns an error if API request has failed, it's used for methods which do not return any result.
+func (e *Envelop) Check() error {
 	if e.Status != "OK" {
-		return fmt.Errorf("API request failed: %v", e.Comment)
+		return &APIError{Method: e.method, Status: e.Status, Comment: e.Comment, Code: e.code}
+	}
+
+	return nil
+}
+
+func (e *Envelop) Unmarshal(v any) error {
+	if err := e.Check(); err != nil {
+		return err
 	}
 
 	if e.Result == nil {
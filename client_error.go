@@ -0,0 +1,53 @@
+package polygon
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrUnauthorized = errors.New("polygon: authentication failed")
+	ErrNotFound     = errors.New("polygon: not found")
+	ErrRateLimited  = errors.New("polygon: rate limit exceeded")
+)
+
+// APIError is returned when polygon API responds with an error, use errors.Is with ErrUnauthorized, ErrNotFound and
+// ErrRateLimited to check the reason.
+type APIError struct {
+	Method  string // API method, e.g. problem.info
+	Status  string // status from the response envelop (normally FAILED), or HTTP status if response is not an envelop
+	Comment string // error description provided by polygon
+	Code    int    // HTTP status code
+}
+
+func (e *APIError) Error() string {
+	if e.Comment == "" {
+		return fmt.Sprintf("API request %v failed: %v (HTTP %v)", e.Method, e.Status, e.Code)
+	}
+
+	return fmt.Sprintf("API request %v failed: %v", e.Method, e.Comment)
+}
+
+func (e *APIError) Is(target error) bool {
+	comment := strings.ToLower(e.Comment)
+
+	switch target {
+	case ErrUnauthorized:
+		return e.Code == http.StatusUnauthorized || e.Code == http.StatusForbidden ||
+			strings.HasPrefix(comment, "apikey:") || strings.HasPrefix(comment, "apisig:") ||
+			strings.Contains(comment, "access denied") || strings.Contains(comment, "don't have access")
+	case ErrNotFound:
+		return e.Code == http.StatusNotFound || strings.Contains(comment, "not found")
+	case ErrRateLimited:
+		return e.Code == http.StatusTooManyRequests || strings.Contains(comment, "too many requests")
+	default:
+		return false
+	}
+}
+
+// transient returns true if request may succeed if repeated
+func (e *APIError) transient() bool {
+	return e.Code >= 500 || e.Code == http.StatusTooManyRequests
+}
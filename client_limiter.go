@@ -0,0 +1,102 @@
+package polygon
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// limiter throttles requests made with the same API key, it combines token bucket (requests per second with bursts)
+// and a cap on number of requests in flight. Request is considered in flight until its response body is closed.
+type limiter struct {
+	lock   sync.Mutex
+	rate   float64 // tokens added per second, zero means unlimited
+	burst  float64 // bucket capacity
+	tokens float64
+	last   time.Time
+	slots  chan struct{} // semaphore for requests in flight, nil means unlimited
+}
+
+func newLimiter(rate float64, burst, inflight int) *limiter {
+	l := &limiter{rate: rate, burst: float64(max(burst, 1)), tokens: float64(max(burst, 1)), last: time.Now()}
+
+	if inflight > 0 {
+		l.slots = make(chan struct{}, inflight)
+	}
+
+	return l
+}
+
+// acquire waits for a free slot and a token, returned function must be called to free the slot
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	release := sync.OnceFunc(func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	})
+
+	if err := l.wait(ctx); err != nil {
+		release()
+		return nil, err
+	}
+
+	return release, nil
+}
+
+// wait takes a token from the bucket, waiting for it to be refilled if necessary
+func (l *limiter) wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	l.lock.Lock()
+
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	// reserve token, balance might go negative which makes next callers wait longer
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+
+	l.lock.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// give reserved token back
+		l.lock.Lock()
+		l.tokens++
+		l.lock.Unlock()
+
+		return ctx.Err()
+	}
+}
+
+// limitedBody frees limiter slot when response body is closed
+type limitedBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *limitedBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
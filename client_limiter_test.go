@@ -0,0 +1,94 @@
+package polygon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_RateLimit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("limit requests in flight", func(t *testing.T) {
+		var current, peak atomic.Int32
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := current.Add(1)
+			defer current.Add(-1)
+
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+
+			_, _ = w.Write([]byte(`{"status":"OK","result":[]}`))
+		}))
+
+		defer srv.Close()
+
+		poly := New("key", "secret", UseBaseURL(srv.URL+"/api/"), UseHTTPClient(srv.Client()), UseRateLimit(0, 0, 2))
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Go(func() {
+				if _, err := poly.ViewTags(ctx, ViewTagsInput{ProblemID: 123}); err != nil {
+					t.Error(err)
+				}
+			})
+		}
+
+		wg.Wait()
+
+		if got := peak.Load(); got > 2 {
+			t.Errorf("Number of requests in flight must not exceed 2, got %v", got)
+		}
+	})
+
+	t.Run("limit request rate", func(t *testing.T) {
+		poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+			return []string{}, nil
+		})
+
+		UseRateLimit(50, 1, 0)(poly)
+
+		start := time.Now()
+
+		for i := 0; i < 6; i++ {
+			if _, err := poly.ViewTags(ctx, ViewTagsInput{ProblemID: 123}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// first request uses burst, other 5 have to wait 20ms each
+		if got := time.Since(start); got < 90*time.Millisecond {
+			t.Errorf("Requests must be throttled, but 6 requests took only %v", got)
+		}
+	})
+
+	t.Run("stop waiting when context is done", func(t *testing.T) {
+		poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+			return []string{}, nil
+		})
+
+		UseRateLimit(0.1, 1, 0)(poly)
+
+		if _, err := poly.ViewTags(ctx, ViewTagsInput{ProblemID: 123}); err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		if _, err := poly.ViewTags(ctx, ViewTagsInput{ProblemID: 123}); err == nil {
+			t.Errorf("Request must fail when context is done")
+		}
+	})
+}
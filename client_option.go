@@ -1,5 +1,7 @@
 package polygon
 
+import "time"
+
 func UseBaseURL(base string) func(*Client) {
 	return func(cli *Client) {
 		cli.base = base
@@ -11,3 +13,37 @@ func UseHTTPClient(hc httpClient) func(*Client) {
 		cli.cli = hc
 	}
 }
+
+// UseRetry enables retrying requests failed due to network errors, server errors or rate limiting. Delay between
+// attempts starts with the given value and grows exponentially with random jitter. Only read requests are retried,
+// requests which change the problem (sent as POST) are made once.
+func UseRetry(retries int, delay time.Duration) func(*Client) {
+	return func(cli *Client) {
+		cli.retries = retries
+		cli.delay = delay
+	}
+}
+
+// UseRateLimit throttles requests to the given rate (requests per second) allowing bursts of the given size, and caps
+// number of requests in flight (including unfinished downloads). Zero rate or inflight disables corresponding limit.
+//
+// Limits are shared by all goroutines using the client, use the same client for all requests made with the same key.
+func UseRateLimit(rate float64, burst, inflight int) func(*Client) {
+	return func(cli *Client) {
+		cli.limiter = newLimiter(rate, burst, inflight)
+	}
+}
+
+// UseClock sets function used to get current time for the request signature.
+func UseClock(now func() time.Time) func(*Client) {
+	return func(cli *Client) {
+		cli.now = now
+	}
+}
+
+// UseSalt sets function used to generate salt for the request signature, salt must be 6 characters long.
+func UseSalt(salt func() string) func(*Client) {
+	return func(cli *Client) {
+		cli.salt = salt
+	}
+}
@@ -0,0 +1,119 @@
+package polygon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// packageWindow is the minimal number of bytes requested at once when package is read at random positions, zip reader
+// makes many small reads and each of them would be a separate request otherwise
+const packageWindow = 1 << 20
+
+// packageReader reads package archive at random positions using ranged downloads, so a single file can be taken from
+// the archive without downloading all of it
+type packageReader struct {
+	ctx    context.Context
+	client *Client
+	input  DownloadPackageInput
+	size   int64
+
+	offset int64  // position of the buffered window in the archive
+	window []byte // last downloaded part of the archive
+}
+
+// newPackageReader downloads the tail of the package archive, which contains zip central directory, and finds out the
+// archive size
+func (c *Client) newPackageReader(ctx context.Context, in DownloadPackageInput) (*packageReader, error) {
+	download, err := c.downloadRange(ctx, in, fmt.Sprintf("bytes=-%d", packageWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	defer download.Close()
+
+	if download.Size < 0 {
+		return nil, errors.New("server does not report size of the package archive")
+	}
+
+	reader := &packageReader{ctx: ctx, client: c, input: in, size: download.Size}
+
+	// server does not support ranges, the tail is downloaded later on demand
+	if download.Offset == 0 && download.Size > packageWindow {
+		return reader, nil
+	}
+
+	if reader.window, err = io.ReadAll(download); err != nil {
+		return nil, err
+	}
+
+	reader.offset = download.Offset
+
+	return reader, nil
+}
+
+func (r *packageReader) ReadAt(data []byte, offset int64) (int, error) {
+	if offset >= r.size {
+		return 0, io.EOF
+	}
+
+	if offset < r.offset || offset+int64(len(data)) > r.offset+int64(len(r.window)) {
+		if err := r.fetch(offset, min(max(int64(len(data)), packageWindow), r.size-offset)); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(data, r.window[offset-r.offset:])
+	if n < len(data) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+// fetch downloads size bytes starting at offset into the window
+func (r *packageReader) fetch(offset, size int64) error {
+	download, err := r.client.downloadRange(r.ctx, r.input, fmt.Sprintf("bytes=%d-%d", offset, offset+size-1))
+	if err != nil {
+		return err
+	}
+
+	defer download.Close()
+
+	if download.Offset != offset {
+		if download.Offset != 0 {
+			return fmt.Errorf("server streams archive from byte %v, but %v was requested", download.Offset, offset)
+		}
+
+		// server does not support ranges, skip to the requested position
+		if _, err := io.CopyN(io.Discard, download, offset); err != nil {
+			return err
+		}
+	}
+
+	window := make([]byte, size)
+	if _, err := io.ReadFull(download, window); err != nil {
+		return err
+	}
+
+	r.offset, r.window = offset, window
+
+	return nil
+}
+
+// downloadRange requests part of the package archive, rng is a value of the Range header
+func (c *Client) downloadRange(ctx context.Context, in DownloadPackageInput, rng string) (*Download, error) {
+	resp, err := c.send(ctx, http.MethodGet, "problem.package", map[string]string{
+		"problemId": fmt.Sprint(in.ProblemID),
+		"packageId": fmt.Sprint(in.PackageID),
+		"type":      in.Type,
+	}, http.Header{"Range": {rng}})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newDownload(resp), nil
+}
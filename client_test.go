@@ -0,0 +1,698 @@
+package polygon
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// apiMock serves polygon API responses, handler receives method name and request parameters and returns result,
+// results of type []byte are written as is (i.e. file downloads), anything else is wrapped into an envelop
+func apiMock(t *testing.T, handler func(method string, params map[string]string) (any, error)) *Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Unable to parse request form: %v", err)
+		}
+
+		params := map[string]string{}
+		for k := range r.Form {
+			params[k] = r.Form.Get(k)
+		}
+
+		result, err := handler(r.URL.Path[len("/api/"):], params)
+		if err != nil {
+			_ = json.NewEncoder(w).Encode(map[string]any{"status": "FAILED", "comment": err.Error()})
+			return
+		}
+
+		if data, ok := result.([]byte); ok {
+			_, _ = w.Write(data)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"status": "OK", "result": result})
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return New("key", "secret", UseBaseURL(srv.URL+"/api/"), UseHTTPClient(srv.Client()))
+}
+
+func TestClient_ProblemInfo(t *testing.T) {
+	ctx := context.Background()
+
+	poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+		if want, got := "problem.info", method; want != got {
+			t.Errorf("Method does not match: want %v, got %v", want, got)
+		}
+
+		if want, got := "123", params["problemId"]; want != got {
+			t.Errorf("Parameter problemId does not match: want %v, got %v", want, got)
+		}
+
+		if params["apiKey"] != "key" || params["apiSig"] == "" || params["time"] == "" {
+			t.Errorf("Request is not signed: %v", params)
+		}
+
+		return map[string]any{"inputFile": "stdin", "outputFile": "stdout", "interactive": true, "timeLimit": 1000, "memoryLimit": 256}, nil
+	})
+
+	got, err := poly.ProblemInfo(ctx, ProblemInfoInput{ProblemID: 123})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &ProblemInfo{InputFile: "stdin", OutputFile: "stdout", Interactive: true, TimeLimit: 1000, MemoryLimit: 256}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("Problem info does not match:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestClient_ViewTags(t *testing.T) {
+	ctx := context.Background()
+
+	poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+		return []string{"dp", "greedy"}, nil
+	})
+
+	got, err := poly.ViewTags(ctx, ViewTagsInput{ProblemID: 123})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"dp", "greedy"}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("Problem tags do not match:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestClient_ViewGeneralDescription(t *testing.T) {
+	ctx := context.Background()
+
+	poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+		if want, got := "problem.viewGeneralDescription", method; want != got {
+			t.Errorf("Method does not match: want %v, got %v", want, got)
+		}
+
+		return "general description", nil
+	})
+
+	got, err := poly.ViewGeneralDescription(ctx, ViewGeneralDescriptionInput{ProblemID: 123})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "general description"; want != got {
+		t.Errorf("Description does not match: want %#v, got %#v", want, got)
+	}
+}
+
+func TestClient_Statements(t *testing.T) {
+	ctx := context.Background()
+
+	poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+		if want, got := "problem.statements", method; want != got {
+			t.Errorf("Method does not match: want %v, got %v", want, got)
+		}
+
+		return map[string]any{"ukrainian": map[string]any{"encoding": "UTF-8", "name": "Сума", "legend": "legend", "input": "input", "output": "output"}}, nil
+	})
+
+	got, err := poly.Statements(ctx, StatementsInput{ProblemID: 123})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]Statement{"ukrainian": {Encoding: "UTF-8", Name: "Сума", Legend: "legend", Input: "input", Output: "output"}}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("Statements do not match:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestClient_ViewFile(t *testing.T) {
+	ctx := context.Background()
+
+	poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+		if want, got := "problem.viewFile", method; want != got {
+			t.Errorf("Method does not match: want %v, got %v", want, got)
+		}
+
+		if params["type"] != "resource" || params["name"] != "image.png" {
+			t.Errorf("File type and name are not passed correctly: %v", params)
+		}
+
+		return []byte("image content"), nil
+	})
+
+	file, err := poly.ViewFile(ctx, ViewFileInput{ProblemID: 123, Type: "resource", Name: "image.png"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	got, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "image content"; want != string(got) {
+		t.Errorf("File content does not match: want %#v, got %#v", want, string(got))
+	}
+}
+
+func TestClient_Files(t *testing.T) {
+	ctx := context.Background()
+
+	poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+		if want, got := "problem.files", method; want != got {
+			t.Errorf("Method does not match: want %v, got %v", want, got)
+		}
+
+		return map[string]any{
+			"resourceFiles": []any{map[string]any{"name": "testlib.h", "length": 10, "resourceAdvancedProperties": map[string]any{"forTypes": "cpp.*", "assets": []string{"CHECKER"}}}},
+			"sourceFiles":   []any{map[string]any{"name": "gen.cpp", "length": 20, "sourceType": "cpp.g++17"}},
+		}, nil
+	})
+
+	got, err := poly.Files(ctx, FilesInput{ProblemID: 123})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Files{
+		ResourceFiles: []File{{Name: "testlib.h", Length: 10, ResourceAdvancedProperties: &ResourceAdvancedProperties{ForTypes: "cpp.*", Assets: []string{"CHECKER"}}}},
+		SourceFiles:   []File{{Name: "gen.cpp", Length: 20, SourceType: "cpp.g++17"}},
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("Files do not match:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestClient_Script(t *testing.T) {
+	ctx := context.Background()
+
+	poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+		if want, got := "tests", params["testset"]; want != got {
+			t.Errorf("Parameter testset does not match: want %v, got %v", want, got)
+		}
+
+		return []byte("gen 1 > $\ngen 2 > $\n"), nil
+	})
+
+	got, err := poly.Script(ctx, ScriptInput{ProblemID: 123, Testset: "tests"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "gen 1 > $\ngen 2 > $\n"; want != got {
+		t.Errorf("Script does not match: want %#v, got %#v", want, got)
+	}
+}
+
+func TestClient_Tests(t *testing.T) {
+	ctx := context.Background()
+
+	poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+		if want, got := "true", params["noInputs"]; want != got {
+			t.Errorf("Parameter noInputs does not match: want %v, got %v", want, got)
+		}
+
+		return []any{
+			map[string]any{"index": 1, "manual": true, "useInStatements": true, "group": "0"},
+			map[string]any{"index": 2, "manual": false, "scriptLine": "gen 10", "group": "1", "points": 50},
+		}, nil
+	})
+
+	got, err := poly.Tests(ctx, TestsInput{ProblemID: 123, Testset: "tests", NoInputs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Test{
+		{Index: 1, Manual: true, UseInStatements: true, Group: "0"},
+		{Index: 2, ScriptLine: "gen 10", Group: "1", Points: 50},
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("Tests do not match:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestClient_TestInput(t *testing.T) {
+	ctx := context.Background()
+
+	poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+		if want, got := "problem.testInput", method; want != got {
+			t.Errorf("Method does not match: want %v, got %v", want, got)
+		}
+
+		if want, got := "2", params["testIndex"]; want != got {
+			t.Errorf("Parameter testIndex does not match: want %v, got %v", want, got)
+		}
+
+		return []byte("1 2\n"), nil
+	})
+
+	file, err := poly.TestInput(ctx, TestInputInput{ProblemID: 123, Testset: "tests", TestIndex: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	got, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "1 2\n"; want != string(got) {
+		t.Errorf("Test input does not match: want %#v, got %#v", want, string(got))
+	}
+}
+
+func TestClient_SaveFile(t *testing.T) {
+	ctx := context.Background()
+
+	source := strings.Repeat("// large source\n", 10000)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if want, got := http.MethodPost, r.Method; want != got {
+			t.Errorf("HTTP method does not match: want %v, got %v", want, got)
+		}
+
+		if r.URL.RawQuery != "" {
+			t.Errorf("Parameters must be sent in request body, got query string %#v", r.URL.RawQuery)
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := source, r.PostForm.Get("file"); want != got {
+			t.Errorf("File content does not match")
+		}
+
+		if want, got := "CHECKER;VALIDATOR", r.PostForm.Get("assets"); want != got {
+			t.Errorf("Parameter assets does not match: want %v, got %v", want, got)
+		}
+
+		if r.PostForm.Get("apiSig") == "" {
+			t.Errorf("Request is not signed")
+		}
+
+		_, _ = w.Write([]byte(`{"status":"OK"}`))
+	}))
+
+	defer srv.Close()
+
+	poly := New("key", "secret", UseBaseURL(srv.URL+"/api/"), UseHTTPClient(srv.Client()))
+
+	err := poly.SaveFile(ctx, SaveFileInput{ProblemID: 123, Type: "resource", Name: "testlib.h", Data: []byte(source), Assets: []string{"CHECKER", "VALIDATOR"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_DownloadPackage(t *testing.T) {
+	ctx := context.Background()
+
+	archive := strings.Repeat("zip", 100)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if want, got := "bytes=100-", r.Header.Get("Range"); want != got {
+			t.Errorf("Range header does not match: want %v, got %v", want, got)
+		}
+
+		http.ServeContent(w, r, "problem.zip", time.Time{}, strings.NewReader(archive))
+	}))
+
+	defer srv.Close()
+
+	poly := New("key", "secret", UseBaseURL(srv.URL+"/api/"), UseHTTPClient(srv.Client()))
+
+	download, err := poly.DownloadPackage(ctx, DownloadPackageInput{ProblemID: 1, PackageID: 2, Type: "windows", Offset: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer download.Close()
+
+	data, err := io.ReadAll(download)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := int64(100), download.Offset; want != got {
+		t.Errorf("Download offset does not match: want %v, got %v", want, got)
+	}
+
+	if want, got := int64(len(archive)), download.Size; want != got {
+		t.Errorf("Download size does not match: want %v, got %v", want, got)
+	}
+
+	if want, got := archive[100:], string(data); want != got {
+		t.Errorf("Downloaded content does not match")
+	}
+}
+
+// countingWriter counts bytes written into the response
+type countingWriter struct {
+	http.ResponseWriter
+	written *atomic.Int64
+}
+
+func (w countingWriter) Write(data []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(data)
+	w.written.Add(int64(n))
+	return n, err
+}
+
+func TestClient_StatementResource(t *testing.T) {
+	ctx := context.Background()
+
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+
+	for _, entry := range []struct{ name, data string }{
+		{name: "tests/01", data: noise(4 << 20)},
+		{name: "statements/english/picture.png", data: "picture content"},
+		{name: "tests/02", data: noise(4 << 20)},
+	} {
+		w, err := writer.CreateHeader(&zip.FileHeader{Name: entry.name, Method: zip.Store})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := w.Write([]byte(entry.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := buffer.Bytes()
+
+	for _, ranges := range []bool{true, false} {
+		t.Run(fmt.Sprintf("ranges=%v", ranges), func(t *testing.T) {
+			var written atomic.Int64
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !ranges {
+					r.Header.Del("Range")
+				}
+
+				http.ServeContent(countingWriter{ResponseWriter: w, written: &written}, r, "problem.zip", time.Time{}, bytes.NewReader(archive))
+			}))
+
+			defer srv.Close()
+
+			poly := New("key", "secret", UseBaseURL(srv.URL+"/api/"), UseHTTPClient(srv.Client()))
+
+			file, err := poly.StatementResource(ctx, StatementResourceInput{ProblemID: 1, PackageID: 2, Type: "windows", Language: "english", Name: "picture.png"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer file.Close()
+
+			data, err := io.ReadAll(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if want, got := "picture content", string(data); want != got {
+				t.Errorf("Resource content does not match: want %#v, got %#v", want, got)
+			}
+
+			if ranges && written.Load() >= int64(len(archive)) {
+				t.Errorf("Only part of the archive must be downloaded, got %v bytes out of %v", written.Load(), len(archive))
+			}
+		})
+	}
+}
+
+func TestClient_CommitChanges(t *testing.T) {
+	ctx := context.Background()
+
+	poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+		return nil, errors.New("problem is locked")
+	})
+
+	err := poly.CommitChanges(ctx, CommitChangesInput{ProblemID: 123, Message: "update tests"})
+	if err == nil || !strings.Contains(err.Error(), "problem is locked") {
+		t.Errorf("Commit must fail with API error comment, got %v instead", err)
+	}
+}
+
+func TestClient_WaitPackage(t *testing.T) {
+	ctx := context.Background()
+
+	attempt := 0
+
+	poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+		attempt++
+
+		state := "RUNNING"
+		if attempt == 3 {
+			state = "READY"
+		}
+
+		return []any{
+			map[string]any{"id": 1, "revision": 1, "state": "READY", "type": "windows"},
+			map[string]any{"id": 2, "revision": 2, "state": state, "type": "windows"},
+		}, nil
+	})
+
+	got, err := poly.WaitPackage(ctx, WaitPackageInput{ProblemID: 123, After: 1, Interval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Package{ID: 2, Revision: 2, State: "READY", Type: "windows"}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("Package does not match:\n%s", cmp.Diff(want, got))
+	}
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+			return []any{map[string]any{"id": 2, "state": "RUNNING", "type": "windows"}}, nil
+		})
+
+		if _, err := poly.WaitPackage(ctx, WaitPackageInput{ProblemID: 123, After: 1, Interval: time.Millisecond}); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Waiting must stop when context is done, got %v instead", err)
+		}
+	})
+}
+
+func TestClient_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	tt := map[string]struct {
+		code    int
+		comment string
+		want    error
+	}{
+		"invalid key":       {code: http.StatusBadRequest, comment: "apiKey: Invalid apiKey", want: ErrUnauthorized},
+		"forbidden":         {code: http.StatusForbidden, want: ErrUnauthorized},
+		"problem not found": {code: http.StatusBadRequest, comment: "problemId: Problem not found", want: ErrNotFound},
+		"too many requests": {code: http.StatusTooManyRequests, want: ErrRateLimited},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.code)
+				if tc.comment != "" {
+					_ = json.NewEncoder(w).Encode(map[string]any{"status": "FAILED", "comment": tc.comment})
+				}
+			}))
+
+			defer srv.Close()
+
+			poly := New("key", "secret", UseBaseURL(srv.URL+"/api/"), UseHTTPClient(srv.Client()))
+
+			_, err := poly.ProblemInfo(ctx, ProblemInfoInput{ProblemID: 123})
+			if !errors.Is(err, tc.want) {
+				t.Errorf("Error must be %v, got %v instead", tc.want, err)
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Error must be APIError, got %T instead", err)
+			}
+
+			if want, got := (APIError{Method: "problem.info", Status: apiErr.Status, Comment: tc.comment, Code: tc.code}), *apiErr; want != got {
+				t.Errorf("Error does not match: want %#v, got %#v", want, got)
+			}
+		})
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retry server errors", func(t *testing.T) {
+		attempts := 0
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+
+			if attempts < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"status":"OK","result":["dp"]}`))
+		}))
+
+		defer srv.Close()
+
+		poly := New("key", "secret", UseBaseURL(srv.URL+"/api/"), UseHTTPClient(srv.Client()), UseRetry(3, time.Millisecond))
+
+		if _, err := poly.ViewTags(ctx, ViewTagsInput{ProblemID: 123}); err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := 3, attempts; want != got {
+			t.Errorf("Number of attempts does not match: want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("do not retry client errors", func(t *testing.T) {
+		attempts := 0
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"FAILED","comment":"problemId: Problem not found"}`))
+		}))
+
+		defer srv.Close()
+
+		poly := New("key", "secret", UseBaseURL(srv.URL+"/api/"), UseHTTPClient(srv.Client()), UseRetry(3, time.Millisecond))
+
+		if _, err := poly.ViewTags(ctx, ViewTagsInput{ProblemID: 123}); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Error must be ErrNotFound, got %v instead", err)
+		}
+
+		if want, got := 1, attempts; want != got {
+			t.Errorf("Number of attempts does not match: want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("do not retry changes", func(t *testing.T) {
+		attempts := 0
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+
+		defer srv.Close()
+
+		poly := New("key", "secret", UseBaseURL(srv.URL+"/api/"), UseHTTPClient(srv.Client()), UseRetry(3, time.Millisecond))
+
+		if _, err := poly.CreateProblem(ctx, CreateProblemInput{Name: "a-plus-b"}); err == nil {
+			t.Error("Request must fail")
+		}
+
+		if want, got := 1, attempts; want != got {
+			t.Errorf("Number of attempts does not match: want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("give up after all retries", func(t *testing.T) {
+		attempts := 0
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+
+		defer srv.Close()
+
+		poly := New("key", "secret", UseBaseURL(srv.URL+"/api/"), UseHTTPClient(srv.Client()), UseRetry(2, time.Millisecond))
+
+		var apiErr *APIError
+		if _, err := poly.ViewTags(ctx, ViewTagsInput{ProblemID: 123}); !errors.As(err, &apiErr) || apiErr.Code != http.StatusServiceUnavailable {
+			t.Errorf("Error must be APIError with code 503, got %v instead", err)
+		}
+
+		if want, got := 3, attempts; want != got {
+			t.Errorf("Number of attempts does not match: want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("do not retry TLS errors", func(t *testing.T) {
+		connections := 0
+
+		srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("Request must not reach the server with untrusted certificate")
+		}))
+
+		srv.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				connections++
+			}
+		}
+
+		srv.StartTLS()
+		defer srv.Close()
+
+		// default client does not trust certificate of the test server
+		poly := New("key", "secret", UseBaseURL(srv.URL+"/api/"), UseHTTPClient(&http.Client{}), UseRetry(3, time.Millisecond))
+
+		if _, err := poly.ProblemInfo(ctx, ProblemInfoInput{ProblemID: 123}); err == nil {
+			t.Fatal("Request must fail")
+		}
+
+		srv.Close()
+
+		if want, got := 1, connections; want != got {
+			t.Errorf("Number of attempts does not match: want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("do not retry unsupported scheme", func(t *testing.T) {
+		attempts := 0
+
+		poly := New("key", "secret", UseBaseURL("ftp://polygon.codeforces.com/api/"), UseRetry(3, time.Millisecond),
+			UseHTTPClient(httpClientFunc(func(req *http.Request) (*http.Response, error) {
+				attempts++
+				return http.DefaultClient.Do(req)
+			})),
+		)
+
+		if _, err := poly.ProblemInfo(ctx, ProblemInfoInput{ProblemID: 123}); err == nil {
+			t.Fatal("Request must fail")
+		}
+
+		if want, got := 1, attempts; want != got {
+			t.Errorf("Number of attempts does not match: want %v, got %v", want, got)
+		}
+	})
+}
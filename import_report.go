@@ -0,0 +1,98 @@
+package polygon
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ImportReport contains information collected during import which does not fit into the snapshot.
+type ImportReport struct {
+	CheckerTests   []*ImportedCheckerTest   // tests to verify imported checker
+	ValidatorTests []*ImportedValidatorTest // tests to verify imported validator
+	Warnings       []*ImportWarning         // content of the package which was skipped or imported partially
+}
+
+// ImportedCheckerTest is a checker test with its files uploaded to the blob storage.
+type ImportedCheckerTest struct {
+	Index     int
+	InputURL  string
+	OutputURL string
+	AnswerURL string
+	Verdict   string // expected verdict: OK, WRONG_ANSWER, PRESENTATION_ERROR or CRASHED
+}
+
+// ImportedValidatorTest is a validator test with its input uploaded to the blob storage.
+type ImportedValidatorTest struct {
+	Validator string // validator the test belongs to, its name or path of its source if validator has no name
+	Index     int    // index of the test among tests of the validator
+	InputURL  string
+	Verdict   string // expected verdict: VALID or INVALID
+}
+
+// Severity of the import warning.
+type Severity string
+
+const (
+	SeverityInfo    Severity = "INFO"    // element is skipped, but its content is imported from another element
+	SeverityWarning Severity = "WARNING" // element is not supported and its content is dropped
+	SeverityError   Severity = "ERROR"   // element is supported, but could not be imported
+)
+
+// WarningCode identifies the reason of the import warning.
+type WarningCode string
+
+const (
+	WarningUnsupportedFormat   WarningCode = "UNSUPPORTED_FORMAT"   // statement or tutorial is not in LaTeX
+	WarningUnsupportedLanguage WarningCode = "UNSUPPORTED_LANGUAGE" // language is not known to LocaleFromLanguage
+	WarningUnmappedRuntime     WarningCode = "UNMAPPED_RUNTIME"     // source type is not in RuntimeMapping
+	WarningUnmappedTag         WarningCode = "UNMAPPED_TAG"         // solution tag has no counterpart
+	WarningUnmappedGroup       WarningCode = "UNMAPPED_GROUP"       // test belongs to a group which is not mapped to a testset
+	WarningInvalidTag          WarningCode = "INVALID_TAG"          // eolymp specific tag can not be parsed
+	WarningMissingFile         WarningCode = "MISSING_FILE"         // file referenced in problem.xml is not in the package
+	WarningUnreadableFile      WarningCode = "UNREADABLE_FILE"      // file can not be read or parsed
+	WarningUploadFailed        WarningCode = "UPLOAD_FAILED"        // file can not be uploaded to the blob storage
+)
+
+// ImportWarning describes a problem.xml element which was skipped or imported partially.
+type ImportWarning struct {
+	Code     WarningCode
+	Severity Severity
+	Element  string // affected problem.xml element, e.g. statements/statement[@path='statements/english/problem.tex']
+	Message  string
+}
+
+// StrictError is returned in strict import mode (see UseStrictImport) when package content would be dropped, it lists
+// every violation found in the package.
+type StrictError struct {
+	Warnings []*ImportWarning
+}
+
+func (e *StrictError) Error() string {
+	messages := make([]string, 0, len(e.Warnings))
+	for _, warning := range e.Warnings {
+		messages = append(messages, fmt.Sprintf("%v: %v", warning.Element, warning.Message))
+	}
+
+	return fmt.Sprintf("strict import has failed, %v element(s) would be dropped: %v", len(e.Warnings), strings.Join(messages, "; "))
+}
+
+// strict returns StrictError if report has warnings about dropped content, informational warnings are ignored
+func (r *ImportReport) strict() error {
+	var violations []*ImportWarning
+	for _, warning := range r.Warnings {
+		if warning.Severity != SeverityInfo {
+			violations = append(violations, warning)
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &StrictError{Warnings: violations}
+}
+
+// normalizeVerdict converts verdict from problem.xml format (wrong-answer) to the API format (WRONG_ANSWER)
+func normalizeVerdict(verdict string) string {
+	return strings.ToUpper(strings.ReplaceAll(verdict, "-", "_"))
+}
@@ -0,0 +1,16 @@
+// Package apisig implements polygon API request signature, it is shared by the client and the fake polygon server.
+package apisig
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"net/url"
+)
+
+// Sign returns signature of API request: salt followed by SHA-512 hash of the salted request, params must include
+// all request parameters except apiSig.
+func Sign(salt, method, secret string, params url.Values) string {
+	hash := sha512.Sum512([]byte(salt + "/" + method + "?" + params.Encode() + "#" + secret))
+
+	return salt + fmt.Sprintf("%x", hash)
+}
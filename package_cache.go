@@ -0,0 +1,254 @@
+package polygon
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PackageKey identifies package archive in the cache.
+type PackageKey struct {
+	Host      string // host (and port) of polygon API, problem IDs are unique within a single polygon instance only
+	ProblemID int
+	PackageID int
+	Revision  int
+}
+
+// String returns key which can be used as a file name, e.g. polygon.codeforces.com-123-456-7.
+func (k PackageKey) String() string {
+	if k.Host == "" {
+		return fmt.Sprintf("%d-%d-%d", k.ProblemID, k.PackageID, k.Revision)
+	}
+
+	host := strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') {
+			return r
+		}
+
+		return '_'
+	}, strings.ToLower(k.Host))
+
+	return fmt.Sprintf("%s-%d-%d-%d", host, k.ProblemID, k.PackageID, k.Revision)
+}
+
+// PackageCache stores downloaded package archives, so the same package is not downloaded twice.
+type PackageCache interface {
+	// Load copies cached archive into dst file, it returns false if there is no such archive in the cache.
+	Load(ctx context.Context, key PackageKey, dst string) (bool, error)
+	// Store copies archive from src file into the cache.
+	Store(ctx context.Context, key PackageKey, src string) error
+}
+
+// DiskCache is a PackageCache which keeps archives in a local directory and evicts least recently used archives when
+// their total size exceeds the limit.
+type DiskCache struct {
+	dir   string
+	limit int64 // maximum total size of archives in bytes, 0 means no limit
+
+	lock    sync.Mutex
+	seq     uint64
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	size int64
+	used uint64 // sequence number of the last access
+}
+
+// NewDiskCache creates cache in the directory dir limited to limit bytes (0 means no limit), archives stored by previous
+// runs are reused and temporary files left by interrupted writes are removed.
+func NewDiskCache(dir string, limit int64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create cache directory: %w", err)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read cache directory: %w", err)
+	}
+
+	var infos []fs.FileInfo
+	for _, file := range files {
+		if !file.IsDir() && filepath.Ext(file.Name()) == ".tmp" {
+			if err := os.Remove(filepath.Join(dir, file.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("unable to remove temporary file: %w", err)
+			}
+
+			continue
+		}
+
+		if file.IsDir() || filepath.Ext(file.Name()) != ".zip" {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read cache directory: %w", err)
+		}
+
+		infos = append(infos, info)
+	}
+
+	// restore access order from modification times, Load touches archives it reads
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ModTime().Before(infos[j].ModTime())
+	})
+
+	cache := &DiskCache{dir: dir, limit: limit, entries: map[string]*cacheEntry{}}
+
+	for _, info := range infos {
+		cache.seq++
+		cache.entries[strings.TrimSuffix(info.Name(), ".zip")] = &cacheEntry{size: info.Size(), used: cache.seq}
+	}
+
+	return cache, nil
+}
+
+// Load copies cached archive into dst, the lock is held only to look up and update entries, so archives are copied
+// concurrently.
+func (c *DiskCache) Load(ctx context.Context, key PackageKey, dst string) (bool, error) {
+	c.lock.Lock()
+	entry, ok := c.entries[key.String()]
+	c.lock.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+
+	path := c.path(key)
+
+	if err := copyFile(path, dst); errors.Is(err, fs.ErrNotExist) {
+		// archive is evicted in the meantime, forget the entry unless it's replaced by a newer one
+		c.lock.Lock()
+		if c.entries[key.String()] == entry {
+			delete(c.entries, key.String())
+		}
+		c.lock.Unlock()
+
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	c.lock.Lock()
+	c.seq++
+	entry.used = c.seq
+	c.lock.Unlock()
+
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+
+	return true, nil
+}
+
+// Store copies archive into the cache, the lock is held only to update entries and evict old archives. Archives which
+// are not readable zip files are rejected, so broken downloads are not served from the cache.
+func (c *DiskCache) Store(ctx context.Context, key PackageKey, src string) error {
+	reader, err := zip.OpenReader(src)
+	if err != nil {
+		return fmt.Errorf("unable to store package archive: %w", err)
+	}
+
+	_ = reader.Close()
+
+	// write into a temporary file first, so interrupted or concurrent writes do not leave broken archives
+	temp, err := os.CreateTemp(c.dir, key.String()+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to store package archive: %w", err)
+	}
+
+	_ = temp.Close()
+
+	if err := copyFile(src, temp.Name()); err != nil {
+		_ = os.Remove(temp.Name())
+		return err
+	}
+
+	info, err := os.Stat(temp.Name())
+	if err != nil {
+		_ = os.Remove(temp.Name())
+		return fmt.Errorf("unable to store package archive: %w", err)
+	}
+
+	if err := os.Rename(temp.Name(), c.path(key)); err != nil {
+		_ = os.Remove(temp.Name())
+		return fmt.Errorf("unable to store package archive: %w", err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.seq++
+	c.entries[key.String()] = &cacheEntry{size: info.Size(), used: c.seq}
+
+	return c.evict()
+}
+
+// evict least recently used archives until total size fits into the limit, must be called with the lock held
+func (c *DiskCache) evict() error {
+	if c.limit <= 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(c.entries))
+	total := int64(0)
+
+	for name, entry := range c.entries {
+		names = append(names, name)
+		total += entry.size
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		return c.entries[names[i]].used < c.entries[names[j]].used
+	})
+
+	for _, name := range names {
+		if total <= c.limit {
+			break
+		}
+
+		if err := os.Remove(filepath.Join(c.dir, name+".zip")); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("unable to evict package archive: %w", err)
+		}
+
+		total -= c.entries[name].size
+		delete(c.entries, name)
+	}
+
+	return nil
+}
+
+func (c *DiskCache) path(key PackageKey) string {
+	return filepath.Join(c.dir, key.String()+".zip")
+}
+
+// copyFile copies content of src file into dst file
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
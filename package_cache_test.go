@@ -0,0 +1,228 @@
+package polygon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eolymp/go-polygon/polygontest"
+)
+
+func TestDiskCache(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	archive := func(size int) string {
+		return filepath.Join(writeZip(t, zipEntry{name: "tests/01", data: noise(size)}), "problem.zip")
+	}
+
+	// each archive is slightly larger than 1000 bytes, so only two of them fit
+	cache, err := NewDiskCache(dir, 2500)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := PackageKey{ProblemID: 1, PackageID: 10, Revision: 5}
+	b := PackageKey{ProblemID: 2, PackageID: 20, Revision: 7}
+	c := PackageKey{ProblemID: 3, PackageID: 30, Revision: 9}
+
+	for _, key := range []PackageKey{a, b} {
+		if err := cache.Store(ctx, key, archive(1000)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// use a, so b becomes least recently used
+	dst := filepath.Join(t.TempDir(), "problem.zip")
+	if hit, err := cache.Load(ctx, a, dst); err != nil || !hit {
+		t.Fatalf("Package %v must be cached, got hit=%v, err=%v", a, hit, err)
+	}
+
+	if data, _ := os.ReadFile(dst); string(data) != zipArchive(t, 1000) {
+		t.Errorf("Loaded archive does not match, got %v bytes", len(data))
+	}
+
+	if err := cache.Store(ctx, c, archive(1000)); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[PackageKey]bool{a: true, b: false, c: true} {
+		if hit, _ := cache.Load(ctx, key, dst); hit != want {
+			t.Errorf("Package %v cached=%v, want %v", key, hit, want)
+		}
+	}
+
+	// broken archives must not be stored
+	broken := filepath.Join(t.TempDir(), "problem.zip")
+	if err := os.WriteFile(broken, []byte(zipArchive(t, 1000)[:500]), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cache.Store(ctx, b, broken); err == nil {
+		t.Errorf("Archive which is not a valid zip must not be stored")
+	}
+
+	if hit, _ := cache.Load(ctx, b, dst); hit {
+		t.Errorf("Package %v must not be cached", b)
+	}
+
+	// cache must survive restart, temporary files left by interrupted writes are removed
+	leftover := filepath.Join(dir, b.String()+".123.tmp")
+	if err := os.WriteFile(leftover, []byte("partial"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewDiskCache(dir, 2500)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hit, _ := reopened.Load(ctx, c, dst); !hit {
+		t.Errorf("Package %v must be cached after restart", c)
+	}
+
+	if _, err := os.Stat(leftover); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Temporary file must be removed on start, got %v", err)
+	}
+}
+
+func TestPackageKey_String(t *testing.T) {
+	tests := map[string]PackageKey{
+		"1-10-5":                        {ProblemID: 1, PackageID: 10, Revision: 5},
+		"polygon.codeforces.com-1-10-5": {Host: "Polygon.Codeforces.com", ProblemID: 1, PackageID: 10, Revision: 5},
+		"127.0.0.1_8080-1-10-5":         {Host: "127.0.0.1:8080", ProblemID: 1, PackageID: 10, Revision: 5},
+		"___1__8080-1-10-5":             {Host: "[::1]:8080", ProblemID: 1, PackageID: 10, Revision: 5},
+	}
+
+	for want, key := range tests {
+		if got := key.String(); want != got {
+			t.Errorf("Key does not match: want %v, got %v", want, got)
+		}
+	}
+}
+
+func TestDiskCache_Concurrent(t *testing.T) {
+	ctx := context.Background()
+
+	cache, err := NewDiskCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := filepath.Join(writeZip(t, zipEntry{name: "tests/01", data: noise(1 << 20)}), "problem.zip")
+
+	info, err := os.Stat(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			key := PackageKey{ProblemID: i % 2, PackageID: 1, Revision: 1}
+			if err := cache.Store(ctx, key, src); err != nil {
+				t.Error(err)
+				return
+			}
+
+			dst := filepath.Join(t.TempDir(), "problem.zip")
+			if hit, err := cache.Load(ctx, key, dst); err != nil || !hit {
+				t.Errorf("Package %v must be cached, got hit=%v, err=%v", key, hit, err)
+				return
+			}
+
+			if data, _ := os.ReadFile(dst); int64(len(data)) != info.Size() {
+				t.Errorf("Loaded archive does not match, got %v bytes", len(data))
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestProblemLoader_FetchCached(t *testing.T) {
+	ctx := context.Background()
+
+	srv := polygontest.NewServer("key", "secret")
+	defer srv.Close()
+
+	srv.AddProblem(123, ".testdata/01-topics")
+
+	cache, err := NewDiskCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var checksums []string
+
+	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t}, UsePackageCache(cache), UseDownloadProgress(func(p Progress) {
+		if p.Checksum != "" {
+			checksums = append(checksums, p.Checksum)
+		}
+	}))
+
+	poly := New("key", "secret", UseBaseURL(srv.BaseURL()))
+
+	for i := 0; i < 2; i++ {
+		_, _, err := loader.fetch(ctx, func(ctx context.Context, path string) error {
+			return loader.downloadByID(ctx, path, poly, 123, defaultPackagePolicy, "")
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if want, got := 1, srv.Calls("problem.package"); want != got {
+		t.Errorf("Package must be downloaded once: want %v downloads, got %v", want, got)
+	}
+
+	// problem with the same ID on another polygon instance is a different problem
+	mirror := polygontest.NewServer("key", "secret")
+	defer mirror.Close()
+
+	mirror.AddProblem(123, ".testdata/01-topics")
+
+	_, _, err = loader.fetch(ctx, func(ctx context.Context, path string) error {
+		return loader.downloadByID(ctx, path, New("key", "secret", UseBaseURL(mirror.BaseURL())), 123, defaultPackagePolicy, "")
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := 1, mirror.Calls("problem.package"); want != got {
+		t.Errorf("Package from another host must not be loaded from cache: want %v downloads, got %v", want, got)
+	}
+
+	if len(checksums) != 3 || checksums[0] != checksums[1] {
+		t.Errorf("Checksum must be reported for downloaded and cached archives, got %v", checksums)
+	}
+
+	// cached archives are verified against expected checksum as well
+	_, _, err = loader.fetch(ctx, func(ctx context.Context, path string) error {
+		return loader.downloadByID(ctx, path, poly, 123, defaultPackagePolicy, strings.Repeat("0", 64))
+	})
+
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Fetch must fail with ErrChecksumMismatch, got %v instead", err)
+	}
+
+	// cached archives are subject to the size limit as well
+	limited := NewProblemLoader(&assetMock{}, &loggerMock{t: t}, UsePackageCache(cache), UseMaxArchiveSize(10))
+
+	_, _, err = limited.fetch(ctx, func(ctx context.Context, path string) error {
+		return limited.downloadByID(ctx, path, poly, 123, defaultPackagePolicy, "")
+	})
+
+	if !errors.Is(err, ErrArchiveTooLarge) {
+		t.Errorf("Fetch must fail with ErrArchiveTooLarge, got %v instead", err)
+	}
+}
@@ -0,0 +1,225 @@
+package polygontest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// redacted parameters are replaced with this value in the cassette
+const redacted = "REDACTED"
+
+// Doer is an HTTP client, it's satisfied by *http.Client.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Interaction is a single recorded HTTP exchange.
+type Interaction struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`            // normalized URL, see normalize
+	Form   string      `json:"form,omitempty"` // normalized form body
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body"`
+}
+
+// Recorder is an HTTP client which records polygon API exchanges into a cassette file and replays them later.
+//
+// Credentials (apiKey, apiSig, login and password parameters and URL user info) are never written to the cassette.
+// Requests are matched by HTTP method, URL and form body ignoring credentials, time and signature (which includes
+// random salt), so replay works regardless of the key and the clock used by the client. Requests with malformed query
+// or form fail without being sent, because their credentials can not be redacted.
+type Recorder struct {
+	path   string
+	next   Doer // nil in replay mode
+	lock   sync.Mutex
+	tape   []Interaction
+	played []bool
+}
+
+// NewRecorder replays interactions from the cassette file if it exists, otherwise it sends requests using next client
+// and records them. Call Save to write recorded interactions to the cassette file.
+func NewRecorder(path string, next Doer) (*Recorder, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Recorder{path: path, next: next}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read cassette: %w", err)
+	}
+
+	var tape []Interaction
+	if err := json.Unmarshal(data, &tape); err != nil {
+		return nil, fmt.Errorf("unable to decode cassette: %w", err)
+	}
+
+	return &Recorder{path: path, tape: tape, played: make([]bool, len(tape))}, nil
+}
+
+// Recording returns true if recorder sends requests and records them, and false if it replays a cassette.
+func (r *Recorder) Recording() bool {
+	return r.next != nil
+}
+
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	var form []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		form = data
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	// parameters which can not be parsed can not be redacted either, so such requests are rejected
+	link, err := normalizeURL(req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to normalize request URL: %w", err)
+	}
+
+	body, err := normalizeQuery(string(form))
+	if err != nil {
+		return nil, fmt.Errorf("unable to normalize request form: %w", err)
+	}
+
+	key := Interaction{Method: req.Method, URL: link, Form: body}
+
+	if r.Recording() {
+		return r.record(req, key)
+	}
+
+	return r.replay(req, key)
+}
+
+// Save writes recorded interactions to the cassette file, it does nothing in replay mode.
+func (r *Recorder) Save() error {
+	if !r.Recording() {
+		return nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	data, err := json.MarshalIndent(r.tape, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.path, data, 0644)
+}
+
+func (r *Recorder) record(req *http.Request, key Interaction) (*http.Response, error) {
+	resp, err := r.next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Status = resp.StatusCode
+	key.Header = http.Header{}
+	key.Body = body
+
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		key.Header.Set("Content-Type", ct)
+	}
+
+	r.lock.Lock()
+	r.tape = append(r.tape, key)
+	r.lock.Unlock()
+
+	return response(req, key), nil
+}
+
+// replay finds the first interaction matching the request which is not played yet
+func (r *Recorder) replay(req *http.Request, key Interaction) (*http.Response, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for i, rec := range r.tape {
+		if r.played[i] || rec.Method != key.Method || rec.URL != key.URL || rec.Form != key.Form {
+			continue
+		}
+
+		r.played[i] = true
+
+		return response(req, rec), nil
+	}
+
+	return nil, fmt.Errorf("cassette %v has no interaction for %v %v", r.path, key.Method, key.URL)
+}
+
+func response(req *http.Request, rec Interaction) *http.Response {
+	header := rec.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(rec.Body)),
+		ContentLength: int64(len(rec.Body)),
+		Request:       req,
+	}
+}
+
+// normalizeURL removes credentials, time and signature from the URL
+func normalizeURL(u *url.URL) (string, error) {
+	query, err := normalizeQuery(u.RawQuery)
+	if err != nil {
+		return "", err
+	}
+
+	n := *u
+	n.User = nil
+	n.RawQuery = query
+
+	return n.String(), nil
+}
+
+// normalizeQuery removes credentials, time and signature from URL encoded parameters, it fails if parameters are
+// malformed rather than returning them as is with credentials
+func normalizeQuery(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return "", err
+	}
+
+	for name := range values {
+		switch strings.ToLower(name) {
+		case "apikey", "login", "password":
+			values.Set(name, redacted)
+		case "apisig", "time":
+			values.Del(name)
+		}
+	}
+
+	return values.Encode(), nil
+}
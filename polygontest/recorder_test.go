@@ -0,0 +1,141 @@
+package polygontest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eolymp/go-polygon"
+	"github.com/eolymp/go-polygon/polygontest"
+)
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+
+	srv := polygontest.NewServer("secret-key", "secret-value")
+	srv.AddProblem(123, "../.testdata/01-topics")
+
+	// record
+	recorder, err := polygontest.NewRecorder(cassette, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !recorder.Recording() {
+		t.Fatal("Recorder must record when cassette does not exist")
+	}
+
+	poly := polygon.New("secret-key", "secret-value", polygon.UseBaseURL(srv.BaseURL()), polygon.UseHTTPClient(recorder))
+
+	want, err := poly.ListPackages(ctx, polygon.ListPackagesInput{ProblemID: 123})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Close()
+
+	data, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), "secret-key") || strings.Contains(string(data), "apiSig") {
+		t.Errorf("Cassette must not contain credentials:\n%s", data)
+	}
+
+	// replay with a different key, clock and salt
+	replayer, err := polygontest.NewRecorder(cassette, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if replayer.Recording() {
+		t.Fatal("Recorder must replay when cassette exists")
+	}
+
+	poly = polygon.New("other-key", "other-secret",
+		polygon.UseBaseURL(srv.BaseURL()),
+		polygon.UseHTTPClient(replayer),
+		polygon.UseClock(func() time.Time { return time.Now().Add(time.Hour) }),
+	)
+
+	got, err := poly.ListPackages(ctx, polygon.ListPackagesInput{ProblemID: 123})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 1 || got[0] != want[0] {
+		t.Errorf("Replayed packages do not match: want %v, got %v", want, got)
+	}
+
+	// interactions are replayed once, so repeated request is not matched
+	if _, err := poly.ListPackages(ctx, polygon.ListPackagesInput{ProblemID: 123}); err == nil {
+		t.Errorf("Request without recorded interaction must fail")
+	}
+}
+
+func TestRecorder_MalformedQuery(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+
+	next := doerFunc(func(req *http.Request) (*http.Response, error) {
+		t.Errorf("Request with malformed parameters must not be sent")
+		return nil, errors.New("unexpected request")
+	})
+
+	recorder, err := polygontest.NewRecorder(cassette, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, link := range []string{
+		"https://polygon.codeforces.com/api/problem.packages?apiKey=secret-key&problemId=%zz",
+		"https://polygon.codeforces.com/api/problem.packages?apiKey=secret-key;problemId=123",
+	} {
+		req, err := http.NewRequest(http.MethodGet, link, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := recorder.Do(req); err == nil {
+			t.Errorf("Request %v must fail", link)
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "https://polygon.codeforces.com/p/eolymp/problem", strings.NewReader("login=user&password=secret-value%"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := recorder.Do(req); err == nil {
+		t.Errorf("Request with malformed form must fail")
+	}
+
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), "secret-key") || strings.Contains(string(data), "secret-value") {
+		t.Errorf("Cassette must not contain credentials:\n%s", data)
+	}
+}
+
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,271 @@
+// Package polygontest provides in-process fake of polygon API for offline testing.
+//
+// Fake server verifies request signatures and serves problem packages from unpacked package directories (the ones
+// containing problem.xml), packing them into zip archives on the fly:
+//
+//	srv := polygontest.NewServer("key", "secret")
+//	defer srv.Close()
+//
+//	srv.AddProblem(123, ".testdata/01-topics")
+//
+//	poly := polygon.New("key", "secret", polygon.UseBaseURL(srv.BaseURL()))
+//
+// Recorder can be used to capture exchanges with the real polygon API once and replay them in offline tests.
+package polygontest
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/eolymp/go-polygon/internal/apisig"
+)
+
+// Package describes polygon package, it mirrors polygon.Package.
+type Package struct {
+	ID                  int    `json:"id"`
+	Revision            int    `json:"revision"`
+	CreationTimeSeconds int    `json:"creationTimeSeconds"`
+	State               string `json:"state"`
+	Comment             string `json:"comment"`
+	Type                string `json:"type"`
+}
+
+// Failure describes an error server responds with instead of handling a request.
+type Failure struct {
+	Code    int    // HTTP status code
+	Comment string // comment in the response envelop, if empty response body is empty
+}
+
+type problem struct {
+	dir      string
+	packages []Package
+}
+
+type Server struct {
+	*httptest.Server
+
+	key    string
+	secret string
+
+	lock     sync.Mutex
+	problems map[int]*problem
+	failures map[string][]Failure
+	calls    map[string]int
+}
+
+// NewServer starts fake polygon API accepting requests signed with the given key and secret.
+func NewServer(key, secret string) *Server {
+	s := &Server{
+		key:      key,
+		secret:   secret,
+		problems: map[int]*problem{},
+		failures: map[string][]Failure{},
+		calls:    map[string]int{},
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+// BaseURL returns API base URL to be used with polygon.UseBaseURL.
+func (s *Server) BaseURL() string {
+	return s.URL + "/api/"
+}
+
+// AddProblem adds problem with a single ready windows package built from the given directory.
+func (s *Server) AddProblem(id int, dir string) {
+	s.AddPackage(id, dir, Package{ID: 1, Revision: 1, State: "READY", Type: "windows"})
+}
+
+// AddPackage adds package to the problem, package content is built from the given directory.
+func (s *Server) AddPackage(id int, dir string, pack Package) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	p, ok := s.problems[id]
+	if !ok {
+		p = &problem{}
+		s.problems[id] = p
+	}
+
+	p.dir = dir
+	p.packages = append(p.packages, pack)
+}
+
+// Fail makes server respond with the given failures to the next calls of the method, one failure per call.
+func (s *Server) Fail(method string, failures ...Failure) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.failures[method] = append(s.failures[method], failures...)
+}
+
+// Calls returns number of calls of the method, including failed ones.
+func (s *Server) Calls(method string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.calls[method]
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	method := strings.TrimPrefix(r.URL.Path, "/api/")
+
+	if err := r.ParseForm(); err != nil {
+		s.fail(w, Failure{Code: http.StatusBadRequest, Comment: "Unable to parse request: " + err.Error()})
+		return
+	}
+
+	s.lock.Lock()
+	s.calls[method]++
+
+	if failures := s.failures[method]; len(failures) > 0 {
+		s.failures[method] = failures[1:]
+		s.lock.Unlock()
+		s.fail(w, failures[0])
+		return
+	}
+
+	s.lock.Unlock()
+
+	if r.Form.Get("apiKey") != s.key {
+		s.fail(w, Failure{Code: http.StatusBadRequest, Comment: "apiKey: Invalid apiKey"})
+		return
+	}
+
+	if !s.verify(method, r.Form) {
+		s.fail(w, Failure{Code: http.StatusBadRequest, Comment: "apiSig: Incorrect signature"})
+		return
+	}
+
+	switch method {
+	case "problem.packages":
+		s.packages(w, r.Form)
+	case "problem.package":
+		s.download(w, r.Form)
+	default:
+		s.fail(w, Failure{Code: http.StatusBadRequest, Comment: fmt.Sprintf("Unknown method %v", method)})
+	}
+}
+
+// verify apiSig parameter, signature is a 6 character salt followed by SHA-512 hash of the salted request
+func (s *Server) verify(method string, params url.Values) bool {
+	sig := params.Get("apiSig")
+	if len(sig) < 6 || params.Get("time") == "" {
+		return false
+	}
+
+	query := url.Values{}
+	for k, v := range params {
+		if k != "apiSig" {
+			query[k] = v
+		}
+	}
+
+	return sig == apisig.Sign(sig[:6], method, s.secret, query)
+}
+
+func (s *Server) problem(params url.Values) (*problem, bool) {
+	id, err := strconv.Atoi(params.Get("problemId"))
+	if err != nil {
+		return nil, false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	p, ok := s.problems[id]
+	return p, ok
+}
+
+func (s *Server) packages(w http.ResponseWriter, params url.Values) {
+	p, ok := s.problem(params)
+	if !ok {
+		s.fail(w, Failure{Code: http.StatusBadRequest, Comment: "problemId: Problem not found"})
+		return
+	}
+
+	s.respond(w, p.packages)
+}
+
+func (s *Server) download(w http.ResponseWriter, params url.Values) {
+	p, ok := s.problem(params)
+	if !ok {
+		s.fail(w, Failure{Code: http.StatusBadRequest, Comment: "problemId: Problem not found"})
+		return
+	}
+
+	found := false
+	for _, pack := range p.packages {
+		if fmt.Sprint(pack.ID) == params.Get("packageId") && pack.State == "READY" {
+			found = true
+		}
+	}
+
+	if !found {
+		s.fail(w, Failure{Code: http.StatusBadRequest, Comment: "packageId: Package not found"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+
+	writer := zip.NewWriter(w)
+
+	err := filepath.WalkDir(p.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		name, err := filepath.Rel(p.dir, path)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		file, err := writer.Create(filepath.ToSlash(name))
+		if err != nil {
+			return err
+		}
+
+		_, err = file.Write(data)
+		return err
+	})
+
+	if err != nil {
+		// headers are sent already, so the best we can do is to break the archive
+		panic(http.ErrAbortHandler)
+	}
+
+	_ = writer.Close()
+}
+
+func (s *Server) respond(w http.ResponseWriter, result any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"status": "OK", "result": result})
+}
+
+func (s *Server) fail(w http.ResponseWriter, f Failure) {
+	if f.Comment == "" {
+		w.WriteHeader(f.Code)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(f.Code)
+	_ = json.NewEncoder(w).Encode(map[string]any{"status": "FAILED", "comment": f.Comment})
+}
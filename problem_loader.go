@@ -9,17 +9,20 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"math"
 	"mime"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/andybalholm/crlf"
@@ -33,18 +36,68 @@ import (
 
 const objectChunkSize = 5242880
 
+// defaultPolygonHost is used by polygon:// links without host
+const defaultPolygonHost = "polygon.codeforces.com"
+
 var imageFinder = regexp.MustCompile("(\\\\includegraphics.*?{)(.+?)(})")
 
 type ProblemLoader struct {
 	assets assetUploader
 	log    logger
+	policy PackagePolicy // which package to import
+	limit  int           // number of problems fetched in parallel by FetchContest
+
+	progress   func(Progress) // optional download progress callback
+	maxArchive int64          // maximum size of problem archive in bytes, 0 means no limit
+	resume     int            // number of attempts to resume interrupted download
+	cache      PackageCache   // optional cache of downloaded packages
+
+	resolvers map[string]SourceResolver // resolvers keyed by link scheme
+	hosts     []string                  // allowed polygon hosts, optionally prefixed with scheme
+
+	unpackLimits UnpackLimits // limits to protect from zip bombs
+	direct       bool         // read package from the archive without unpacking it
+	workspace    string       // directory to create workspaces in, system temp directory by default
+	keep         bool         // keep workspace if import fails
+
+	http     httpClient                                              // HTTP client for all requests made by loader
+	factory  func(key, secret string, opts ...func(*Client)) *Client // creates polygon API clients
+	throttle func(*Client)                                           // optional rate limit for polygon API clients
+
+	clients     map[string]*Client // polygon API clients reused by key, secret and host to share rate limit
+	clientsLock sync.Mutex
+
+	strict bool // fail import if any package content is dropped
 }
 
-func NewProblemLoader(assets assetUploader, log logger) *ProblemLoader {
-	return &ProblemLoader{
+// NewProblemLoader creates loader with default settings, which can be changed with options. By default unpacked
+// archives are limited to 8 GiB in total, 2 GiB per file and 100000 entries, see UseUnpackLimits.
+func NewProblemLoader(assets assetUploader, log logger, opts ...func(*ProblemLoader)) *ProblemLoader {
+	loader := &ProblemLoader{
 		assets: assets,
-		log:    log,
+		log:    &redactedLogger{log: log},
+		policy: defaultPackagePolicy,
+		limit:  3,
+		resume: 3,
+		hosts:  []string{defaultPolygonHost},
+
+		unpackLimits: defaultUnpackLimits,
+
+		http:    http.DefaultClient,
+		factory: New,
+	}
+
+	loader.resolvers = map[string]SourceResolver{
+		"polygon": SourceResolverFunc(loader.resolvePolygon),
+		"https":   SourceResolverFunc(loader.resolvePolygonLink),
+		"http":    SourceResolverFunc(loader.resolvePolygonLink),
 	}
+
+	for _, opt := range opts {
+		opt(loader)
+	}
+
+	return loader
 }
 
 // Fetch downloads, parses and normalizes problem for it to be imported into Eolymp database.
@@ -56,41 +109,110 @@ func NewProblemLoader(assets assetUploader, log logger) *ProblemLoader {
 //   - host, path and port can be omitted
 //
 // An example of a link: polygon://api-key:api-secret@/?problemId=123
-func (p *ProblemLoader) Fetch(ctx context.Context, link string) (*atlaspb.Snapshot, error) {
-	// create workspace
-	path := filepath.Join(os.TempDir(), uuid.New().String())
-	if err := os.Mkdir(path, 0777); err != nil {
-		return nil, fmt.Errorf("unable to create workspace: %w", err)
+//
+// Host and port of the link select polygon instance, they must be allowed with UsePolygonHosts. Links without host
+// use polygon.codeforces.com, which is allowed by default, but it must be listed explicitly if UsePolygonHosts is used.
+//
+// Package to import is picked according to the loader's PackagePolicy, query parameters type, revision and verify
+// can override it for a single link, e.g. polygon://api-key:api-secret@/?problemId=123&revision=12&type=linux
+//
+// Downloaded archive must be a valid zip, query parameter checksum (hex encoded SHA-256) makes loader verify it as
+// well, Fetch fails with ErrChecksumMismatch if archive does not match it.
+//
+// Links to polygon website (https://polygon.codeforces.com/...) are supported as well, other schemes can be enabled
+// with UseFileSource, UseHTTPSource and UseSourceResolver.
+//
+// Along with the snapshot, Fetch returns import report with information which does not fit into the snapshot and
+// warnings about package content which was skipped.
+//
+// Credentials from the link are never included into returned errors or log messages.
+func (p *ProblemLoader) Fetch(ctx context.Context, link string) (*atlaspb.Snapshot, *ImportReport, error) {
+	snapshot, report, err := p.fetch(ctx, func(ctx context.Context, path string) error {
+		return p.download(ctx, path, link)
+	})
+
+	return snapshot, report, redactError(err, linkSecrets(link)...)
+}
+
+// fetch creates workspace, downloads problem archive using given function, unpacks and converts it
+func (p *ProblemLoader) fetch(ctx context.Context, download func(ctx context.Context, path string) error) (snapshot *atlaspb.Snapshot, report *ImportReport, err error) {
+	root := p.workspace
+	if root == "" {
+		root = os.TempDir()
 	}
 
-	defer p.cleanup(path)
+	// create workspace, it's only accessible by the current user as it might contain unpublished problems
+	path := filepath.Join(root, uuid.New().String())
+	if err := os.Mkdir(path, 0700); err != nil {
+		return nil, nil, fmt.Errorf("unable to create workspace: %w", err)
+	}
+
+	defer func() {
+		if err != nil && p.keep {
+			p.log.Printf("Workspace %v is kept for inspection", path)
+			err = &WorkspaceError{Path: path, Err: err}
+			return
+		}
+
+		p.cleanup(path)
+	}()
 
 	start := time.Now()
 
 	p.log.Printf("Downloading problem archive")
 
 	// download and unpack
-	if err := p.download(ctx, path, link); err != nil {
-		return nil, fmt.Errorf("unable to download problem archive: %w", err)
+	if err := download(ctx, path); err != nil {
+		return nil, nil, fmt.Errorf("unable to download problem archive: %w", err)
 	}
 
 	p.log.Printf("Downloaded in %v", time.Since(start))
 
 	start = time.Now()
 
-	if err := p.unpack(ctx, path); err != nil {
-		return nil, fmt.Errorf("unable to unpack problem archive: %w", err)
+	archive := filepath.Join(path, "problem.zip")
+
+	// read package straight from the archive
+	if _, err := os.Stat(archive); err == nil && p.direct {
+		reader, err := zip.OpenReader(archive)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to open problem archive: %w", err)
+		}
+
+		defer reader.Close()
+
+		fsys, err := p.unpackLimits.limitArchive(&reader.Reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to open problem archive: %w", err)
+		}
+
+		return p.SnapshotFS(ctx, fsys)
 	}
 
-	p.log.Printf("Unpacked in %v", time.Since(start))
+	// resolvers may provide already extracted package instead of archive
+	if _, err := os.Stat(archive); err == nil {
+		if err := p.unpack(ctx, path); err != nil {
+			return nil, nil, fmt.Errorf("unable to unpack problem archive: %w", err)
+		}
+
+		p.log.Printf("Unpacked in %v", time.Since(start))
+	}
 
 	return p.Snapshot(ctx, path)
 }
 
-func (p *ProblemLoader) Snapshot(ctx context.Context, path string) (*atlaspb.Snapshot, error) {
-	file, err := os.Open(filepath.Join(path, "problem.xml"))
+// Snapshot parses and normalizes unpacked problem package, information which does not fit into the snapshot is
+// returned in the import report along with warnings about skipped content.
+func (p *ProblemLoader) Snapshot(ctx context.Context, path string) (*atlaspb.Snapshot, *ImportReport, error) {
+	return p.SnapshotFS(ctx, os.DirFS(path))
+}
+
+// SnapshotFS works like Snapshot, but reads problem package from the file system, e.g. *zip.Reader opened on the
+// package archive, so the package does not have to be unpacked.
+func (p *ProblemLoader) SnapshotFS(ctx context.Context, fsys fs.FS) (*atlaspb.Snapshot, *ImportReport, error) {
+	file, err := fsys.Open("problem.xml")
 	if err != nil {
-		return nil, fmt.Errorf("unable to open problem.xml: %w", err)
+		return nil, nil, fmt.Errorf("unable to open problem.xml: %w", err)
 	}
 
 	defer file.Close()
@@ -98,60 +220,72 @@ func (p *ProblemLoader) Snapshot(ctx context.Context, path string) (*atlaspb.Sna
 	spec := &Specification{}
 
 	if err := xml.NewDecoder(file).Decode(spec); err != nil {
-		return nil, fmt.Errorf("unable to decode problem.xml: %w", err)
+		return nil, nil, fmt.Errorf("unable to decode problem.xml: %w", err)
 	}
 
 	p.log.Printf("File package.xml succesfully parsed")
 
+	report := &ImportReport{}
+
 	// import...
-	checker, err := p.checker(ctx, path, spec)
+	checker, err := p.checker(ctx, fsys, spec, report)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read checker configuration: %w", err)
+	}
+
+	validator, err := p.validator(ctx, fsys, spec, report)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read checker configuration: %w", err)
+		return nil, nil, fmt.Errorf("unable to read validator configuration: %w", err)
 	}
 
-	validator, err := p.validator(ctx, path, spec)
+	report.CheckerTests, err = p.checkerTests(ctx, fsys, spec, report)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read validator configuration: %w", err)
+		return nil, nil, fmt.Errorf("unable to read checker tests: %w", err)
 	}
 
-	interactor, err := p.interactor(ctx, path, spec)
+	report.ValidatorTests, err = p.validatorTests(ctx, fsys, spec, report)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read interactor configuration: %w", err)
+		return nil, nil, fmt.Errorf("unable to read validator tests: %w", err)
 	}
 
-	statements, err := p.statements(ctx, path, spec)
+	interactor, err := p.interactor(ctx, fsys, spec, report)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read statements: %w", err)
+		return nil, nil, fmt.Errorf("unable to read interactor configuration: %w", err)
 	}
 
-	templates, err := p.templates(ctx, path, spec)
+	statements, err := p.statements(ctx, fsys, spec, report)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read templates: %w", err)
+		return nil, nil, fmt.Errorf("unable to read statements: %w", err)
 	}
 
-	attachments, err := p.attachments(ctx, path, spec)
+	templates, err := p.templates(ctx, fsys, spec, report)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read attachments (materials): %w", err)
+		return nil, nil, fmt.Errorf("unable to read templates: %w", err)
 	}
 
-	testsets, tests, err := p.testing(ctx, path, spec)
+	attachments, err := p.attachments(ctx, fsys, spec, report)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read tests: %w", err)
+		return nil, nil, fmt.Errorf("unable to read attachments (materials): %w", err)
 	}
 
-	editorials, err := p.editorials(ctx, path, spec)
+	testsets, tests, err := p.testing(ctx, fsys, spec, report)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read tutorials: %w", err)
+		return nil, nil, fmt.Errorf("unable to read tests: %w", err)
 	}
 
-	solutions, err := p.solutions(ctx, path, spec)
+	editorials, err := p.editorials(ctx, fsys, spec, report)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read solutions: %w", err)
+		return nil, nil, fmt.Errorf("unable to read tutorials: %w", err)
 	}
 
-	scripts, err := p.scripts(ctx, path, spec)
+	solutions, err := p.solutions(ctx, fsys, spec, report)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read solutions: %w", err)
+		return nil, nil, fmt.Errorf("unable to read solutions: %w", err)
+	}
+
+	scripts, err := p.scripts(ctx, fsys, spec, report)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read solutions: %w", err)
 	}
 
 	runs := uint32(spec.Judging.RunCount)
@@ -161,12 +295,18 @@ func (p *ProblemLoader) Snapshot(ctx context.Context, path string) (*atlaspb.Sna
 
 	interactiveFollowup := len(spec.Interactor.Runs) > 1
 
+	if p.strict {
+		if err := report.strict(); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	kind := atlaspb.Problem_PROGRAM
 	if spec.Tagged("output-only") {
 		kind = atlaspb.Problem_OUTPUT
 	}
 
-	return &atlaspb.Snapshot{
+	snapshot := &atlaspb.Snapshot{
 		Problem:     &atlaspb.Problem{Topics: TopicsFromTags(spec.Tags), Type: kind},
 		Testing:     &atlaspb.TestingConfig{RunCount: runs, InteractiveFollowup: interactiveFollowup},
 		Checker:     checker,
@@ -180,34 +320,110 @@ func (p *ProblemLoader) Snapshot(ctx context.Context, path string) (*atlaspb.Sna
 		Editorials:  editorials,
 		Solutions:   solutions,
 		Scripts:     scripts,
-	}, nil
+	}
+
+	return snapshot, report, nil
 }
 
-// download problem archive and save it locally for parsing
+// download problem archive and save it locally for parsing, link scheme defines which resolver is used
 func (p *ProblemLoader) download(ctx context.Context, path string, link string) error {
 	origin, err := url.Parse(link)
 	if err != nil {
 		return fmt.Errorf("invalid problem origin: %w", err)
 	}
 
-	switch {
-	case origin.Scheme == "polygon":
-		pid, err := strconv.ParseInt(origin.Query().Get("problemId"), 10, 32)
-		if err != nil {
-			return errors.New("invalid problem origin: query parameter problemId must be a valid integer")
-		}
+	resolver, ok := p.resolvers[origin.Scheme]
+	if !ok {
+		return fmt.Errorf("invalid problem origin: schema %#v is not supported", origin.Scheme)
+	}
 
-		secret, _ := origin.User.Password()
-		poly := New(origin.User.Username(), secret)
+	return resolver.Resolve(ctx, origin, path)
+}
 
-		return p.downloadByID(ctx, path, poly, int(pid))
-	case origin.Scheme == "https" && origin.Hostname() == "polygon.codeforces.com" &&
-		origin.Port() == "":
+// resolvePolygon downloads package using polygon API, link is polygon://api-key:api-secret@/?problemId=123
+func (p *ProblemLoader) resolvePolygon(ctx context.Context, origin *url.URL, path string) error {
+	pid, err := strconv.ParseInt(origin.Query().Get("problemId"), 10, 32)
+	if err != nil {
+		return errors.New("invalid problem origin: query parameter problemId must be a valid integer")
+	}
 
-		return p.downloadByLink(ctx, path, origin)
-	default:
-		return fmt.Errorf("invalid problem origin: schema %#v is not supported", origin.Scheme)
+	policy, err := p.policy.override(origin.Query())
+	if err != nil {
+		return fmt.Errorf("invalid problem origin: %w", err)
+	}
+
+	poly, err := p.client(origin)
+	if err != nil {
+		return err
+	}
+
+	return p.downloadByID(ctx, path, poly, int(pid), policy, origin.Query().Get("checksum"))
+}
+
+// resolvePolygonLink downloads package using link from polygon website
+func (p *ProblemLoader) resolvePolygonLink(ctx context.Context, origin *url.URL, path string) error {
+	if scheme, ok := p.polygonHost(origin.Host); !ok || scheme != origin.Scheme {
+		return fmt.Errorf("invalid problem origin: host %#v is not supported", origin.Host)
+	}
+
+	return p.downloadByLink(ctx, path, origin)
+}
+
+// client creates polygon API client using credentials and host from the link
+func (p *ProblemLoader) client(origin *url.URL) (*Client, error) {
+	secret, _ := origin.User.Password()
+
+	// links without host point to the default polygon instance, which must be allowed as any other host
+	host := origin.Host
+	if host == "" {
+		host = defaultPolygonHost
+	}
+
+	scheme, ok := p.polygonHost(host)
+	if !ok {
+		return nil, fmt.Errorf("invalid problem origin: host %#v is not supported", host)
+	}
+
+	opts := []func(*Client){UseHTTPClient(p.http), UseBaseURL(scheme + "://" + host + "/api/")}
+
+	if p.throttle != nil {
+		opts = append(opts, p.throttle)
+	}
+
+	p.clientsLock.Lock()
+	defer p.clientsLock.Unlock()
+
+	// reuse client for the same key, so that concurrent fetches share its rate limit
+	id := strings.Join([]string{origin.User.Username(), secret, strings.ToLower(host)}, "\x00")
+	if poly, ok := p.clients[id]; ok {
+		return poly, nil
 	}
+
+	if p.clients == nil {
+		p.clients = map[string]*Client{}
+	}
+
+	poly := p.factory(origin.User.Username(), secret, opts...)
+	p.clients[id] = poly
+
+	return poly, nil
+}
+
+// polygonHost checks if host is in the list of allowed polygon hosts and returns scheme it must be accessed with
+func (p *ProblemLoader) polygonHost(host string) (string, bool) {
+	for _, allowed := range p.hosts {
+		scheme := "https"
+
+		if origin, err := url.Parse(allowed); err == nil && origin.Scheme != "" && origin.Host != "" {
+			scheme, allowed = origin.Scheme, origin.Host
+		}
+
+		if strings.EqualFold(allowed, host) {
+			return scheme, true
+		}
+	}
+
+	return "", false
 }
 
 func (p *ProblemLoader) downloadByLink(ctx context.Context, path string, link *url.URL) error {
@@ -221,20 +437,43 @@ func (p *ProblemLoader) downloadByLink(ctx context.Context, path string, link *u
 		query.Set("type", link.Query().Get("type"))
 	}
 
-	req, err := http.NewRequest(http.MethodPost, link.String(), strings.NewReader(query.Encode()))
-	if err != nil {
-		return fmt.Errorf("unable to compose HTTP request: %w", err)
+	// expected checksum is not sent to polygon
+	params := link.Query()
+	checksum := params.Get("checksum")
+
+	if params.Has("checksum") {
+		params.Del("checksum")
+		link.RawQuery = params.Encode()
 	}
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return p.save(ctx, path, checksum, func(ctx context.Context, offset int64) (*Download, error) {
+		req, err := http.NewRequest(http.MethodPost, link.String(), strings.NewReader(query.Encode()))
+		if err != nil {
+			return nil, fmt.Errorf("unable to compose HTTP request: %w", err)
+		}
 
-	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
-	if err != nil {
-		return fmt.Errorf("HTTP request has failed: %w", err)
-	}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		}
+
+		resp, err := p.http.Do(req.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("HTTP request has failed: %w", err)
+		}
 
-	defer resp.Body.Close()
+		if err := p.checkArchiveResponse(link, resp); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+
+		return newDownload(resp), nil
+	})
+}
 
+// checkArchiveResponse makes sure response to the link contains problem archive
+func (p *ProblemLoader) checkArchiveResponse(link *url.URL, resp *http.Response) error {
 	if resp.StatusCode == 404 {
 		return fmt.Errorf("problem link %#v leads to a file which does not exist", link.String())
 	}
@@ -256,66 +495,127 @@ func (p *ProblemLoader) downloadByLink(ctx context.Context, path string, link *u
 		return fmt.Errorf("problem link %#v is invalid: server response code is %v", link.String(), resp.StatusCode)
 	}
 
-	file, err := os.Create(filepath.Join(path, "problem.zip"))
+	return nil
+}
+
+func (p *ProblemLoader) downloadByID(ctx context.Context, path string, poly *Client, id int, policy PackagePolicy, checksum string) error {
+	pack, err := p.pickPackage(ctx, poly, id, policy)
 	if err != nil {
-		return fmt.Errorf("unable to create problem archieve: %w", err)
+		return fmt.Errorf("unable to find package: %w", err)
 	}
 
-	defer file.Close()
+	key := PackageKey{Host: poly.host(), ProblemID: id, PackageID: pack.ID, Revision: pack.Revision}
+	archive := filepath.Join(path, "problem.zip")
+
+	if p.cache != nil {
+		hit, err := p.cache.Load(ctx, key, archive)
+		if err != nil {
+			p.log.Errorf("Unable to load package #%v from cache: %v", pack.ID, err)
+		}
+
+		if hit && err == nil {
+			p.log.Printf("Package #%v (revision %v) is loaded from cache", pack.ID, pack.Revision)
+			return p.load(path, checksum)
+		}
+	}
+
+	err = p.save(ctx, path, checksum, func(ctx context.Context, offset int64) (*Download, error) {
+		return poly.DownloadPackage(ctx, DownloadPackageInput{
+			ProblemID: id,
+			PackageID: pack.ID,
+			Type:      pack.Type,
+			Offset:    offset,
+		})
+	})
 
-	if _, err := io.Copy(file, resp.Body); err != nil {
-		return fmt.Errorf("unable to write problem archieve: %w", err)
+	if err != nil {
+		return fmt.Errorf("unable to download package: %w", err)
+	}
+
+	if p.cache != nil {
+		if err := p.cache.Store(ctx, key, archive); err != nil {
+			p.log.Errorf("Unable to store package #%v in cache: %v", pack.ID, err)
+		}
 	}
 
 	return nil
 }
 
-func (p *ProblemLoader) downloadByID(ctx context.Context, path string, poly *Client, id int) error {
-	pack, err := p.pickPackage(ctx, poly, id)
+// pickPackage to download according to the policy
+func (p *ProblemLoader) pickPackage(ctx context.Context, poly *Client, problem int, policy PackagePolicy) (*Package, error) {
+	packages, err := poly.ListPackages(ctx, ListPackagesInput{ProblemID: problem})
 	if err != nil {
-		return fmt.Errorf("unable to find package: %w", err)
+		return nil, err
 	}
 
-	src, err := poly.DownloadPackage(ctx, DownloadPackageInput{
-		ProblemID: id,
-		PackageID: pack.ID,
-		Type:      pack.Type,
-	})
+	if policy.Current {
+		return p.freshPackage(ctx, poly, problem, packages, policy)
+	}
 
-	if err != nil {
-		return fmt.Errorf("unable to download package: %w", err)
+	revision := policy.Revision
+	if policy.Latest {
+		revision = policy.latest(packages)
+	}
+
+	if pack := policy.pick(packages, revision); pack != nil {
+		return pack, nil
 	}
 
-	defer src.Close()
+	if revision != 0 {
+		return nil, fmt.Errorf("no suitable packages for revision %v", revision)
+	}
+
+	return nil, errors.New("no suitable packages")
+}
 
-	dst, err := os.Create(filepath.Join(path, "problem.zip"))
+// freshPackage picks ready package for the current problem revision or builds a new one
+func (p *ProblemLoader) freshPackage(ctx context.Context, poly *Client, problem int, packages []Package, policy PackagePolicy) (*Package, error) {
+	problems, err := poly.ListProblems(ctx, ListProblemsInput{ID: problem})
 	if err != nil {
-		return fmt.Errorf("unable to create problem archieve: %w", err)
+		return nil, fmt.Errorf("unable to read problem revision: %w", err)
 	}
 
-	defer dst.Close()
+	if len(problems) == 0 {
+		return nil, fmt.Errorf("problem %v does not exist", problem)
+	}
+
+	revision := problems[0].Revision
 
-	if _, err := io.Copy(dst, src); err != nil {
-		return fmt.Errorf("unable to save problem archive locally: %w", err)
+	if pack := policy.pick(packages, revision); pack != nil {
+		return pack, nil
 	}
 
-	return nil
-}
+	start := time.Now()
+	wait := WaitPackageInput{ProblemID: problem}
 
-// pickPackage to download, it has to be in the right status, and it has to be windows, so we can use generated tests
-func (p *ProblemLoader) pickPackage(ctx context.Context, poly *Client, problem int) (*Package, error) {
-	packages, err := poly.ListPackages(ctx, ListPackagesInput{ProblemID: problem})
+	// another import might have started the build already, wait for it instead of queueing a duplicate
+	if pack := policy.building(packages, revision); pack != nil {
+		p.log.Printf("Package #%v for revision %v is being built, waiting for it", pack.ID, revision)
+		wait.ID = pack.ID
+	} else {
+		for _, pack := range packages {
+			wait.After = max(wait.After, pack.ID)
+		}
+
+		p.log.Printf("There is no package for revision %v, building a new one", revision)
+
+		if err := poly.BuildPackage(ctx, BuildPackageInput{ProblemID: problem, Full: policy.full(), Verify: policy.Verify}); err != nil {
+			return nil, fmt.Errorf("unable to build package: %w", err)
+		}
+	}
+
+	pack, err := poly.WaitPackage(ctx, wait)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, pack := range packages {
-		if pack.Type == "windows" && pack.State == "READY" {
-			return &pack, nil
-		}
+	if !policy.accepts(*pack) {
+		return nil, fmt.Errorf("package #%v is %v, but %v package is required", pack.ID, pack.Type, strings.Join(policy.types(), " or "))
 	}
 
-	return nil, errors.New("no suitable packages")
+	p.log.Printf("Package #%v is built in %v", pack.ID, time.Since(start))
+
+	return pack, nil
 }
 
 // unpack problem archive
@@ -327,23 +627,33 @@ func (p *ProblemLoader) unpack(ctx context.Context, path string) error {
 
 	defer reader.Close()
 
+	if err := p.unpackLimits.checkEntries(reader.File); err != nil {
+		return err
+	}
+
+	names := map[string]bool{}
+	total := int64(0)
+
 	for _, file := range reader.File {
-		file := file
+		// sanitize file path
+		name := strings.TrimPrefix(filepath.Clean(filepath.Join("/", file.Name)), string([]rune{filepath.Separator}))
+
+		if err := p.unpackLimits.check(file, names, name); err != nil {
+			return err
+		}
 
 		err := func() error {
-			// sanitize file path
-			name := strings.TrimPrefix(filepath.Clean(filepath.Join("/", file.Name)), string([]rune{filepath.Separator}))
 			fpath := filepath.Join(path, name)
 
 			if file.FileInfo().IsDir() {
-				if err := os.MkdirAll(fpath, 0777); err != nil && !os.IsExist(err) {
+				if err := os.MkdirAll(fpath, 0700); err != nil && !os.IsExist(err) {
 					return fmt.Errorf("unable to create folder %#v: %w", name, err)
 				}
 
 				return nil
 			}
 
-			if err := os.MkdirAll(filepath.Dir(fpath), 0777); err != nil && !os.IsExist(err) {
+			if err := os.MkdirAll(filepath.Dir(fpath), 0700); err != nil && !os.IsExist(err) {
 				return fmt.Errorf("unable to create folder %#v: %w", filepath.Dir(name), err)
 			}
 
@@ -354,14 +664,15 @@ func (p *ProblemLoader) unpack(ctx context.Context, path string) error {
 
 			defer sf.Close()
 
-			df, err := os.Create(fpath)
+			df, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 			if err != nil {
 				return fmt.Errorf("unable to open %#v for writing: %w", name, err)
 			}
 
 			defer df.Close()
 
-			if _, err = io.Copy(df, sf); err != nil {
+			entry := &limitedEntry{file: file, limits: p.unpackLimits, total: &total}
+			if err := entry.copy(df, sf); err != nil {
 				return fmt.Errorf("unable to write %#v: %w", name, err)
 			}
 
@@ -376,6 +687,21 @@ func (p *ProblemLoader) unpack(ctx context.Context, path string) error {
 	return nil
 }
 
+// WorkspaceError is returned by Fetch if import fails and the workspace is kept for inspection, see
+// UseKeepFailedWorkspace.
+type WorkspaceError struct {
+	Path string // location of the workspace with downloaded and unpacked package
+	Err  error
+}
+
+func (e *WorkspaceError) Error() string {
+	return fmt.Sprintf("%v (workspace is kept in %v)", e.Err, e.Path)
+}
+
+func (e *WorkspaceError) Unwrap() error {
+	return e.Err
+}
+
 // cleanup after import
 func (p *ProblemLoader) cleanup(path string) {
 	if err := os.RemoveAll(path); err != nil {
@@ -383,7 +709,39 @@ func (p *ProblemLoader) cleanup(path string) {
 	}
 }
 
-func (p *ProblemLoader) checker(ctx context.Context, path string, spec *Specification) (*atlaspb.Checker, error) {
+// warn adds a warning about skipped content to the import report and writes it to the log
+func (p *ProblemLoader) warn(report *ImportReport, severity Severity, code WarningCode, element, format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+
+	if severity == SeverityInfo {
+		p.log.Printf("%s", message)
+	} else {
+		p.log.Errorf("%s", message)
+	}
+
+	report.Warnings = append(report.Warnings, &ImportWarning{Code: code, Severity: severity, Element: element, Message: message})
+}
+
+// formatSeverity of the skipped statement or tutorial, it's not a loss if the same language is available in LaTeX
+func formatSeverity(latex bool) Severity {
+	if latex {
+		return SeverityInfo
+	}
+
+	return SeverityWarning
+}
+
+// resourceElement formats problem.xml element for a resource file
+func resourceElement(path string) string {
+	return fmt.Sprintf("files/resources/file[@path='%s']", path)
+}
+
+// solutionElement formats problem.xml element for a solution source
+func solutionElement(path string) string {
+	return fmt.Sprintf("assets/solutions/solution/source[@path='%s']", path)
+}
+
+func (p *ProblemLoader) checker(ctx context.Context, fsys fs.FS, spec *Specification, report *ImportReport) (*atlaspb.Checker, error) {
 	switch spec.Checker.Name {
 	case "std::ncmp.cpp": // Single or more int64, ignores whitespaces
 		p.log.Printf("Adding checker std::ncmp.cpp as tokens with precision=0 and case-sensitive=true")
@@ -416,7 +774,7 @@ func (p *ProblemLoader) checker(ctx context.Context, path string, spec *Specific
 				continue
 			}
 
-			data, err := os.ReadFile(filepath.Join(path, checker.Path))
+			data, err := fs.ReadFile(fsys, fsName(checker.Path))
 			if err != nil {
 				return nil, err
 			}
@@ -427,13 +785,13 @@ func (p *ProblemLoader) checker(ctx context.Context, path string, spec *Specific
 					continue
 				}
 
-				asset, err := p.uploadFile(ctx, filepath.Join(path, file.Path))
+				asset, err := p.uploadFile(ctx, fsys, fsName(file.Path))
 				if err != nil {
-					p.log.Errorf("Unable to upload checker extra file %#v: %v", file.Path, err)
+					p.warn(report, SeverityError, WarningUploadFailed, resourceElement(file.Path), "Unable to upload checker extra file %#v: %v", file.Path, err)
 					continue
 				}
 
-				files = append(files, &executorpb.File{Path: filepath.Base(file.Path), SourceUrl: asset})
+				files = append(files, &executorpb.File{Path: path.Base(file.Path), SourceUrl: asset})
 			}
 
 			p.log.Printf("Adding program checker in %v", runtime)
@@ -445,7 +803,7 @@ func (p *ProblemLoader) checker(ctx context.Context, path string, spec *Specific
 	return nil, fmt.Errorf("checker \"%s\" not supported", spec.Checker.Name)
 }
 
-func (p *ProblemLoader) validator(ctx context.Context, path string, spec *Specification) (*atlaspb.Validator, error) {
+func (p *ProblemLoader) validator(ctx context.Context, fsys fs.FS, spec *Specification, report *ImportReport) (*atlaspb.Validator, error) {
 	for _, validator := range spec.Validator {
 		for _, source := range validator.Sources {
 			runtime, ok := RuntimeMapping[source.Type]
@@ -453,7 +811,7 @@ func (p *ProblemLoader) validator(ctx context.Context, path string, spec *Specif
 				continue
 			}
 
-			data, err := os.ReadFile(filepath.Join(path, source.Path))
+			data, err := fs.ReadFile(fsys, fsName(source.Path))
 			if err != nil {
 				return nil, err
 			}
@@ -464,13 +822,13 @@ func (p *ProblemLoader) validator(ctx context.Context, path string, spec *Specif
 					continue
 				}
 
-				asset, err := p.uploadFile(ctx, filepath.Join(path, file.Path))
+				asset, err := p.uploadFile(ctx, fsys, fsName(file.Path))
 				if err != nil {
-					p.log.Errorf("Unable to upload validator extra file %#v: %v", file.Path, err)
+					p.warn(report, SeverityError, WarningUploadFailed, resourceElement(file.Path), "Unable to upload validator extra file %#v: %v", file.Path, err)
 					continue
 				}
 
-				files = append(files, &executorpb.File{Path: filepath.Base(file.Path), SourceUrl: asset})
+				files = append(files, &executorpb.File{Path: path.Base(file.Path), SourceUrl: asset})
 			}
 
 			p.log.Printf("Adding program validator in %v", runtime)
@@ -479,10 +837,75 @@ func (p *ProblemLoader) validator(ctx context.Context, path string, spec *Specif
 		}
 	}
 
+	// none of the sources has mapped runtime, so validator is dropped
+	for _, validator := range spec.Validator {
+		for _, source := range validator.Sources {
+			p.warn(report, SeverityWarning, WarningUnmappedRuntime, fmt.Sprintf("assets/validators/validator/source[@path='%s']", source.Path), "Skipping validator %#v because runtime %#v is not mapped", source.Path, source.Type)
+		}
+	}
+
 	return nil, nil
 }
 
-func (p *ProblemLoader) interactor(ctx context.Context, path string, spec *Specification) (*atlaspb.Interactor, error) {
+// checkerTests uploads checker tests, so checker can be verified after import
+func (p *ProblemLoader) checkerTests(ctx context.Context, fsys fs.FS, spec *Specification, report *ImportReport) (tests []*ImportedCheckerTest, err error) {
+	testset := spec.Checker.Testset
+
+	for index, polytest := range testset.Tests {
+		input := fsName(fmt.Sprintf(testset.InputPathPattern, index+1))
+		output := fsName(fmt.Sprintf(testset.OutputPathPattern, index+1))
+		answer := fsName(fmt.Sprintf(testset.AnswerPathPattern, index+1))
+
+		if !fileExists(fsys, input) || !fileExists(fsys, output) || !fileExists(fsys, answer) {
+			p.warn(report, SeverityWarning, WarningMissingFile, fmt.Sprintf("assets/checker/testset/tests/test[%d]", index+1), "Skipping checker test %#v because some of its files are missing", index+1)
+			continue
+		}
+
+		test := &ImportedCheckerTest{Index: index + 1, Verdict: normalizeVerdict(polytest.Verdict)}
+
+		if test.InputURL, err = p.uploadFile(ctx, fsys, input); err != nil {
+			return nil, fmt.Errorf("unable to upload checker test %v input: %w", index+1, err)
+		}
+
+		if test.OutputURL, err = p.uploadFile(ctx, fsys, output); err != nil {
+			return nil, fmt.Errorf("unable to upload checker test %v output: %w", index+1, err)
+		}
+
+		if test.AnswerURL, err = p.uploadFile(ctx, fsys, answer); err != nil {
+			return nil, fmt.Errorf("unable to upload checker test %v answer: %w", index+1, err)
+		}
+
+		tests = append(tests, test)
+	}
+
+	return tests, nil
+}
+
+// validatorTests uploads validator tests, so validator can be verified after import
+func (p *ProblemLoader) validatorTests(ctx context.Context, fsys fs.FS, spec *Specification, report *ImportReport) (tests []*ImportedValidatorTest, err error) {
+	for _, validator := range spec.Validator {
+		testset := validator.Testset
+
+		for index, polytest := range testset.Tests {
+			input := fsName(fmt.Sprintf(testset.InputPathPattern, index+1))
+			if !fileExists(fsys, input) {
+				p.warn(report, SeverityWarning, WarningMissingFile, fmt.Sprintf("assets/validators/validator[@name='%s']/testset/tests/test[%d]", validator.Name, index+1), "Skipping validator test %#v because its input is missing", index+1)
+				continue
+			}
+
+			link, err := p.uploadFile(ctx, fsys, input)
+			if err != nil {
+				return nil, fmt.Errorf("unable to upload validator test %v input: %w", index+1, err)
+			}
+
+			tests = append(tests, &ImportedValidatorTest{Validator: validator.Identity(), Index: index + 1, InputURL: link, Verdict: normalizeVerdict(polytest.Verdict)})
+		}
+	}
+
+	return tests, nil
+}
+
+func (p *ProblemLoader) interactor(ctx context.Context, fsys fs.FS, spec *Specification, report *ImportReport) (*atlaspb.Interactor, error) {
 	if len(spec.Interactor.Sources) == 0 {
 		return nil, nil
 	}
@@ -493,7 +916,7 @@ func (p *ProblemLoader) interactor(ctx context.Context, path string, spec *Speci
 			continue
 		}
 
-		data, err := os.ReadFile(filepath.Join(path, source.Path))
+		data, err := fs.ReadFile(fsys, fsName(source.Path))
 		if err != nil {
 			return nil, err
 		}
@@ -504,13 +927,13 @@ func (p *ProblemLoader) interactor(ctx context.Context, path string, spec *Speci
 				continue
 			}
 
-			asset, err := p.uploadFile(ctx, filepath.Join(path, file.Path))
+			asset, err := p.uploadFile(ctx, fsys, fsName(file.Path))
 			if err != nil {
-				p.log.Errorf("Unable to upload interactor extra file %#v: %v", file.Path, err)
+				p.warn(report, SeverityError, WarningUploadFailed, resourceElement(file.Path), "Unable to upload interactor extra file %#v: %v", file.Path, err)
 				continue
 			}
 
-			files = append(files, &executorpb.File{Path: filepath.Base(file.Path), SourceUrl: asset})
+			files = append(files, &executorpb.File{Path: path.Base(file.Path), SourceUrl: asset})
 		}
 
 		p.log.Printf("Adding interactor in %v", runtime)
@@ -521,29 +944,31 @@ func (p *ProblemLoader) interactor(ctx context.Context, path string, spec *Speci
 	return nil, errors.New("interactor is not supported")
 }
 
-func (p *ProblemLoader) statements(ctx context.Context, path string, spec *Specification) (statements []*atlaspb.Statement, err error) {
+func (p *ProblemLoader) statements(ctx context.Context, fsys fs.FS, spec *Specification, report *ImportReport) (statements []*atlaspb.Statement, err error) {
 	for _, statement := range spec.Statements {
+		element := fmt.Sprintf("statements/statement[@path='%s']", statement.Path)
+
 		if statement.Type != "application/x-tex" {
-			p.log.Printf("Skipping statement %#v because it has unsupported format %#v", statement.Path, statement.Type)
+			p.warn(report, formatSeverity(spec.HasLatexStatement(statement.Language)), WarningUnsupportedFormat, element, "Skipping statement %#v because it has unsupported format %#v", statement.Path, statement.Type)
 			continue
 		}
 
 		locale, err := LocaleFromLanguage(statement.Language)
 		if err != nil {
-			p.log.Printf("Skipping statement %#v because it has unsupported language: %v", statement.Path, err)
+			p.warn(report, SeverityWarning, WarningUnsupportedLanguage, element, "Skipping statement %#v because it has unsupported language: %v", statement.Path, err)
 			continue
 		}
 
-		data, err := os.ReadFile(filepath.Join(path, filepath.Dir(statement.Path), "problem-properties.json"))
+		data, err := fs.ReadFile(fsys, fsName(path.Dir(statement.Path), "problem-properties.json"))
 		if err != nil {
-			p.log.Errorf("Unable to read statement %#v: %v", statement.Path, err)
+			p.warn(report, SeverityError, WarningUnreadableFile, element, "Unable to read statement %#v: %v", statement.Path, err)
 			continue
 		}
 
 		props := ProblemProperties{}
 
 		if err := json.Unmarshal(data, &props); err != nil {
-			p.log.Errorf("Unable to read problem-properties.json for statement %#v: %v", statement.Path, err)
+			p.warn(report, SeverityError, WarningUnreadableFile, element, "Unable to read problem-properties.json for statement %#v: %v", statement.Path, err)
 			continue
 		}
 
@@ -569,7 +994,7 @@ func (p *ProblemLoader) statements(ctx context.Context, path string, spec *Speci
 		}
 
 		latex := strings.Join(parts, "\n\n")
-		latex = p.uploadImagesFromLatex(ctx, filepath.Join(path, filepath.Dir(statement.Path)), latex)
+		latex = p.uploadImagesFromLatex(ctx, fsys, report, element, path.Dir(statement.Path), latex)
 
 		statements = append(statements, &atlaspb.Statement{
 			Locale:  locale,
@@ -582,26 +1007,28 @@ func (p *ProblemLoader) statements(ctx context.Context, path string, spec *Speci
 	return statements, nil
 }
 
-func (p *ProblemLoader) editorials(ctx context.Context, path string, spec *Specification) (editorials []*atlaspb.Editorial, err error) {
+func (p *ProblemLoader) editorials(ctx context.Context, fsys fs.FS, spec *Specification, report *ImportReport) (editorials []*atlaspb.Editorial, err error) {
 	for _, tutorial := range spec.Tutorials {
+		element := fmt.Sprintf("tutorials/tutorial[@path='%s']", tutorial.Path)
+
 		if tutorial.Type != "application/x-tex" {
-			p.log.Printf("Skipping tutorial %#v because it has unsupported format %#v", tutorial.Path, tutorial.Type)
+			p.warn(report, formatSeverity(spec.HasLatexTutorial(tutorial.Language)), WarningUnsupportedFormat, element, "Skipping tutorial %#v because it has unsupported format %#v", tutorial.Path, tutorial.Type)
 			continue
 		}
 
 		locale, err := LocaleFromLanguage(tutorial.Language)
 		if err != nil {
-			p.log.Printf("Skipping tutorial %#v because it has unsupported language: %v", tutorial.Path, err)
+			p.warn(report, SeverityWarning, WarningUnsupportedLanguage, element, "Skipping tutorial %#v because it has unsupported language: %v", tutorial.Path, err)
 			continue
 		}
 
-		data, err := os.ReadFile(filepath.Join(path, tutorial.Path))
+		data, err := fs.ReadFile(fsys, fsName(tutorial.Path))
 		if err != nil {
-			p.log.Errorf("Unable to read tutorial %#v: %v", tutorial.Path, err)
+			p.warn(report, SeverityError, WarningUnreadableFile, element, "Unable to read tutorial %#v: %v", tutorial.Path, err)
 			continue
 		}
 
-		latex := p.uploadImagesFromLatex(ctx, filepath.Join(path, filepath.Dir(tutorial.Path)), string(data))
+		latex := p.uploadImagesFromLatex(ctx, fsys, report, element, path.Dir(tutorial.Path), string(data))
 
 		editorials = append(editorials, &atlaspb.Editorial{
 			Locale:  locale,
@@ -612,11 +1039,13 @@ func (p *ProblemLoader) editorials(ctx context.Context, path string, spec *Speci
 	return editorials, nil
 }
 
-func (p *ProblemLoader) solutions(ctx context.Context, path string, spec *Specification) (solutions []*atlaspb.Solution, err error) {
+func (p *ProblemLoader) solutions(ctx context.Context, fsys fs.FS, spec *Specification, report *ImportReport) (solutions []*atlaspb.Solution, err error) {
 	for _, solution := range spec.Solutions {
+		element := solutionElement(solution.Source.Path)
+
 		runtime, ok := RuntimeMapping[solution.Source.Type]
 		if !ok {
-			p.log.Errorf("Skipping solution %#v because runtime %#v is not mapped", solution.Source.Path, solution.Source.Type)
+			p.warn(report, SeverityWarning, WarningUnmappedRuntime, element, "Skipping solution %#v because runtime %#v is not mapped", solution.Source.Path, solution.Source.Type)
 			continue
 		}
 
@@ -639,18 +1068,18 @@ func (p *ProblemLoader) solutions(ctx context.Context, path string, spec *Specif
 		case "time-limit-exceeded-or-memory-limit-exceeded", "presentation-error":
 			kind = atlaspb.Solution_DONT_RUN
 		default:
-			p.log.Errorf("Skipping solution %#v because tag %#v is not mapped", solution.Source.Path, solution.Tag)
+			p.warn(report, SeverityWarning, WarningUnmappedTag, element, "Skipping solution %#v because tag %#v is not mapped", solution.Source.Path, solution.Tag)
 			continue
 		}
 
-		data, err := os.ReadFile(filepath.Join(path, solution.Source.Path))
+		data, err := fs.ReadFile(fsys, fsName(solution.Source.Path))
 		if err != nil {
-			p.log.Errorf("Unable to read solution file %#v: %v", solution.Source.Path, err)
+			p.warn(report, SeverityError, WarningUnreadableFile, element, "Unable to read solution file %#v: %v", solution.Source.Path, err)
 			continue
 		}
 
 		solutions = append(solutions, &atlaspb.Solution{
-			Name:    filepath.Base(solution.Source.Path),
+			Name:    path.Base(solution.Source.Path),
 			Runtime: runtime,
 			Source:  string(data),
 			Type:    kind,
@@ -660,39 +1089,41 @@ func (p *ProblemLoader) solutions(ctx context.Context, path string, spec *Specif
 	return solutions, nil
 }
 
-func (p *ProblemLoader) scripts(ctx context.Context, path string, spec *Specification) (scripts []*atlaspb.Script, err error) {
+func (p *ProblemLoader) scripts(ctx context.Context, fsys fs.FS, spec *Specification, report *ImportReport) (scripts []*atlaspb.Script, err error) {
 	for _, script := range spec.Executables {
+		element := fmt.Sprintf("files/executables/executable/source[@path='%s']", script.Source.Path)
+
 		runtime, ok := RuntimeMapping[script.Source.Type]
 		if !ok {
-			p.log.Errorf("Skipping script %#v because runtime %#v is not mapped", script.Source.Path, script.Source.Type)
+			p.warn(report, SeverityWarning, WarningUnmappedRuntime, element, "Skipping script %#v because runtime %#v is not mapped", script.Source.Path, script.Source.Type)
 			continue
 		}
 
 		lang := strings.Split(runtime, ":")[0]
 
-		data, err := os.ReadFile(filepath.Join(path, script.Source.Path))
+		data, err := fs.ReadFile(fsys, fsName(script.Source.Path))
 		if err != nil {
-			p.log.Errorf("Unable to read script file %#v: %v", script.Source.Path, err)
+			p.warn(report, SeverityError, WarningUnreadableFile, element, "Unable to read script file %#v: %v", script.Source.Path, err)
 			continue
 		}
 
 		var files []*executorpb.File
 		for _, file := range spec.Resources {
-			if filepath.Ext(file.Path) != ".h" || (lang != "cpp" && lang != "c") {
+			if path.Ext(file.Path) != ".h" || (lang != "cpp" && lang != "c") {
 				continue
 			}
 
-			asset, err := p.uploadFile(ctx, filepath.Join(path, file.Path))
+			asset, err := p.uploadFile(ctx, fsys, fsName(file.Path))
 			if err != nil {
-				p.log.Errorf("Unable to upload solution extra file %#v: %v", file.Path, err)
+				p.warn(report, SeverityError, WarningUploadFailed, resourceElement(file.Path), "Unable to upload solution extra file %#v: %v", file.Path, err)
 				continue
 			}
 
-			files = append(files, &executorpb.File{Path: filepath.Base(file.Path), SourceUrl: asset})
+			files = append(files, &executorpb.File{Path: path.Base(file.Path), SourceUrl: asset})
 		}
 
 		scripts = append(scripts, &atlaspb.Script{
-			Name:    strings.TrimSuffix(filepath.Base(script.Source.Path), filepath.Ext(script.Source.Path)),
+			Name:    strings.TrimSuffix(path.Base(script.Source.Path), path.Ext(script.Source.Path)),
 			Runtime: runtime,
 			Source:  string(data),
 			Files:   files,
@@ -706,13 +1137,13 @@ func (p *ProblemLoader) scripts(ctx context.Context, path string, spec *Specific
 
 		runtime, ok := RuntimeMapping[solution.Source.Type]
 		if !ok {
-			p.log.Errorf("Unable to create solution script because runtime %#v is not mapped", solution.Source.Type)
+			p.warn(report, SeverityWarning, WarningUnmappedRuntime, solutionElement(solution.Source.Path), "Unable to create solution script because runtime %#v is not mapped", solution.Source.Type)
 			continue
 		}
 
-		data, err := os.ReadFile(filepath.Join(path, solution.Source.Path))
+		data, err := fs.ReadFile(fsys, fsName(solution.Source.Path))
 		if err != nil {
-			p.log.Errorf("Unable to read solution script: %v", err)
+			p.warn(report, SeverityError, WarningUnreadableFile, solutionElement(solution.Source.Path), "Unable to read solution script: %v", err)
 			continue
 		}
 
@@ -722,13 +1153,13 @@ func (p *ProblemLoader) scripts(ctx context.Context, path string, spec *Specific
 				continue
 			}
 
-			asset, err := p.uploadFile(ctx, filepath.Join(path, file.Path))
+			asset, err := p.uploadFile(ctx, fsys, fsName(file.Path))
 			if err != nil {
-				p.log.Errorf("Unable to upload solution extra file %#v: %v", file.Path, err)
+				p.warn(report, SeverityError, WarningUploadFailed, resourceElement(file.Path), "Unable to upload solution extra file %#v: %v", file.Path, err)
 				continue
 			}
 
-			files = append(files, &executorpb.File{Path: filepath.Base(file.Path), SourceUrl: asset})
+			files = append(files, &executorpb.File{Path: path.Base(file.Path), SourceUrl: asset})
 		}
 
 		scripts = append(scripts, &atlaspb.Script{
@@ -743,7 +1174,7 @@ func (p *ProblemLoader) scripts(ctx context.Context, path string, spec *Specific
 }
 
 // todo: add grader to the templates
-func (p *ProblemLoader) templates(ctx context.Context, path string, spec *Specification) (templates []*atlaspb.Template, err error) {
+func (p *ProblemLoader) templates(ctx context.Context, fsys fs.FS, spec *Specification, report *ImportReport) (templates []*atlaspb.Template, err error) {
 	for lang, runtimes := range TemplateMapping {
 		ext, ok := LanguageExtensions[lang]
 		if !ok {
@@ -756,8 +1187,8 @@ func (p *ProblemLoader) templates(ctx context.Context, path string, spec *Specif
 		}
 
 		// try to load template file
-		source, err := os.ReadFile(filepath.Join(path, "files", filename))
-		if err != nil && !os.IsNotExist(err) {
+		source, err := fs.ReadFile(fsys, fsName("files", filename))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
 			return nil, err
 		}
 
@@ -768,17 +1199,17 @@ func (p *ProblemLoader) templates(ctx context.Context, path string, spec *Specif
 				continue
 			}
 
-			name := filepath.Base(file.Path)
+			name := path.Base(file.Path)
 
-			data, err := os.ReadFile(filepath.Join(path, file.Path))
+			data, err := fs.ReadFile(fsys, fsName(file.Path))
 			if err != nil {
-				p.log.Errorf("Unable to read resource file %#v: %v", file.Path, err)
+				p.warn(report, SeverityError, WarningUnreadableFile, resourceElement(file.Path), "Unable to read resource file %#v: %v", file.Path, err)
 				continue
 			}
 
 			asset, err := p.assets.UploadAsset(ctx, &assetpb.UploadAssetInput{Name: name, Data: data})
 			if err != nil {
-				p.log.Errorf("Unable to upload attachment file %#v: %v", file.Path, err)
+				p.warn(report, SeverityError, WarningUploadFailed, resourceElement(file.Path), "Unable to upload attachment file %#v: %v", file.Path, err)
 				continue
 			}
 
@@ -805,23 +1236,23 @@ func (p *ProblemLoader) templates(ctx context.Context, path string, spec *Specif
 	return
 }
 
-func (p *ProblemLoader) attachments(ctx context.Context, path string, spec *Specification) (attachments []*atlaspb.Attachment, err error) {
+func (p *ProblemLoader) attachments(ctx context.Context, fsys fs.FS, spec *Specification, report *ImportReport) (attachments []*atlaspb.Attachment, err error) {
 	for _, material := range spec.Materials {
 		if material.Publish != "with-statement" {
 			continue
 		}
 
-		data, err := os.ReadFile(filepath.Join(path, material.Path))
+		data, err := fs.ReadFile(fsys, fsName(material.Path))
 		if err != nil {
-			p.log.Errorf("Unable to read material %#v: %v", material.Path, err)
+			p.warn(report, SeverityError, WarningUnreadableFile, fmt.Sprintf("materials/material[@path='%s']", material.Path), "Unable to read material %#v: %v", material.Path, err)
 			continue
 		}
 
-		name := filepath.Base(material.Path)
+		name := path.Base(material.Path)
 
 		asset, err := p.assets.UploadAsset(ctx, &assetpb.UploadAssetInput{Name: name, Data: data})
 		if err != nil {
-			p.log.Errorf("Unable to upload material %#v: %v", material.Path, err)
+			p.warn(report, SeverityError, WarningUploadFailed, fmt.Sprintf("materials/material[@path='%s']", material.Path), "Unable to upload material %#v: %v", material.Path, err)
 			continue
 		}
 
@@ -829,21 +1260,21 @@ func (p *ProblemLoader) attachments(ctx context.Context, path string, spec *Spec
 	}
 
 	for _, file := range spec.Resources {
-		if !strings.HasPrefix(filepath.Base(file.Path), "pub_") {
+		if !strings.HasPrefix(path.Base(file.Path), "pub_") {
 			continue
 		}
 
-		data, err := os.ReadFile(filepath.Join(path, file.Path))
+		data, err := fs.ReadFile(fsys, fsName(file.Path))
 		if err != nil {
-			p.log.Errorf("Unable to read attachment file %#v: %v", file.Path, err)
+			p.warn(report, SeverityError, WarningUnreadableFile, resourceElement(file.Path), "Unable to read attachment file %#v: %v", file.Path, err)
 			continue
 		}
 
-		name := strings.TrimPrefix(filepath.Base(file.Path), "pub_")
+		name := strings.TrimPrefix(path.Base(file.Path), "pub_")
 
 		asset, err := p.assets.UploadAsset(ctx, &assetpb.UploadAssetInput{Name: name, Data: data})
 		if err != nil {
-			p.log.Errorf("Unable to upload attachment file %#v: %v", file.Path, err)
+			p.warn(report, SeverityError, WarningUploadFailed, resourceElement(file.Path), "Unable to upload attachment file %#v: %v", file.Path, err)
 			continue
 		}
 
@@ -853,7 +1284,7 @@ func (p *ProblemLoader) attachments(ctx context.Context, path string, spec *Spec
 	return
 }
 
-func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specification) (testsets []*atlaspb.Testset, tests []*atlaspb.Test, err error) {
+func (p *ProblemLoader) testing(ctx context.Context, fsys fs.FS, spec *Specification, report *ImportReport) (testsets []*atlaspb.Testset, tests []*atlaspb.Test, err error) {
 	// don't bother if there are no tests
 	if len(spec.Judging.Testsets) < 0 {
 		return
@@ -876,7 +1307,7 @@ func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specific
 			blockMin = true
 		case strings.HasPrefix(tag.Value, "eolymp_tl="):
 			if val, err := strconv.Atoi(tag.Value[10:]); err != nil {
-				p.log.Errorf("Found eolymp_tl tag, but unable to parse it: %v", err)
+				p.warn(report, SeverityWarning, WarningInvalidTag, fmt.Sprintf("tags/tag[@value='%s']", tag.Value), "Found eolymp_tl tag, but unable to parse it: %v", err)
 			} else {
 				p.log.Printf("Found eolymp_tl tag, overriding time limit to %v ms", val)
 				timeLimit = val
@@ -884,7 +1315,7 @@ func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specific
 
 		case strings.HasPrefix(tag.Value, "eolymp_ml="):
 			if val, err := strconv.Atoi(tag.Value[10:]); err != nil {
-				p.log.Errorf("Found eolymp_ml tag, but unable to parse it: %v", err)
+				p.warn(report, SeverityWarning, WarningInvalidTag, fmt.Sprintf("tags/tag[@value='%s']", tag.Value), "Found eolymp_ml tag, but unable to parse it: %v", err)
 			} else {
 				p.log.Printf("Found eolymp_ml tag, overriding memory limit to %v bytes", val)
 				memLimit = val
@@ -958,7 +1389,7 @@ func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specific
 	for index, polytest := range polyset.Tests {
 		testset, ok := testsetByGroup[polytest.Group]
 		if !ok {
-			p.log.Errorf("Skipping test %#v because its group %#v is not mapped", index+1, polytest.Group)
+			p.warn(report, SeverityWarning, WarningUnmappedGroup, fmt.Sprintf("judging/testset[@name='%s']/tests/test[%d]", polyset.Name, index+1), "Skipping test %#v because its group %#v is not mapped", index+1, polytest.Group)
 			continue
 		}
 
@@ -970,25 +1401,25 @@ func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specific
 		}
 
 		// make input
-		input := filepath.Join(path, fmt.Sprintf(polyset.InputPathPattern, index+1))
-		if polytest.Method == "generated" && !fileExists(input) {
+		input := fsName(fmt.Sprintf(polyset.InputPathPattern, index+1))
+		if polytest.Method == "generated" && !fileExists(fsys, input) {
 			command := strings.Split(polytest.Command, " ")
 			test.Input = &atlaspb.Test_InputGenerator{InputGenerator: &atlaspb.Test_Generator{ScriptName: command[0], Arguments: command[1:]}}
 		} else {
 			eg.Go(func() error {
-				link, err := p.uploadFile(ctx, input)
+				link, err := p.uploadFile(ctx, fsys, input)
 				test.Input = &atlaspb.Test_InputUrl{InputUrl: link}
 				return err
 			})
 		}
 
 		// make answer
-		answer := filepath.Join(path, fmt.Sprintf(polyset.AnswerPathPattern, index+1))
-		if !fileExists(answer) {
+		answer := fsName(fmt.Sprintf(polyset.AnswerPathPattern, index+1))
+		if !fileExists(fsys, answer) {
 			test.Answer = &atlaspb.Test_AnswerGenerator{AnswerGenerator: &atlaspb.Test_Generator{ScriptName: "solution"}}
 		} else {
 			eg.Go(func() error {
-				link, err := p.uploadFile(ctx, answer)
+				link, err := p.uploadFile(ctx, fsys, answer)
 				test.Answer = &atlaspb.Test_AnswerUrl{AnswerUrl: link}
 				return err
 			})
@@ -1003,23 +1434,23 @@ func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specific
 					continue
 				}
 
-				base := filepath.Join(path, filepath.Dir(s.Path))
-				sampleInput := filepath.Join(base, fmt.Sprintf("example.%02d", index+1))
-				sampleAnswer := filepath.Join(base, fmt.Sprintf("example.%02d.a", index+1))
+				base := path.Dir(s.Path)
+				sampleInput := fsName(base, fmt.Sprintf("example.%02d", index+1))
+				sampleAnswer := fsName(base, fmt.Sprintf("example.%02d.a", index+1))
 
-				if fileExists(sampleInput) && !sampleInputOk {
+				if fileExists(fsys, sampleInput) && !sampleInputOk {
 					sampleInputOk = true
 					eg.Go(func() error {
-						link, err := p.uploadFile(ctx, sampleInput)
+						link, err := p.uploadFile(ctx, fsys, sampleInput)
 						test.ExampleInput = &atlaspb.Test_ExampleInputUrl{ExampleInputUrl: link}
 						return err
 					})
 				}
 
-				if fileExists(sampleAnswer) && !sampleAnswerOk {
+				if fileExists(fsys, sampleAnswer) && !sampleAnswerOk {
 					sampleAnswerOk = true
 					eg.Go(func() error {
-						link, err := p.uploadFile(ctx, sampleAnswer)
+						link, err := p.uploadFile(ctx, fsys, sampleAnswer)
 						test.ExampleAnswer = &atlaspb.Test_ExampleAnswerUrl{ExampleAnswerUrl: link}
 						return err
 					})
@@ -1054,13 +1485,14 @@ func (p *ProblemLoader) testing(ctx context.Context, path string, spec *Specific
 
 // uploadImagesFromLatex finds images in text, uploads them and replaces original names with links.
 // e.g. \includegraphics[width=12cm]{myimage.png} -> \includegraphics[width=12cm]{https://...}
-func (p *ProblemLoader) uploadImagesFromLatex(ctx context.Context, path, text string) string {
+// Images which can not be uploaded are reported as warnings for the element the text comes from.
+func (p *ProblemLoader) uploadImagesFromLatex(ctx context.Context, fsys fs.FS, report *ImportReport, element, dir, text string) string {
 	images := imageFinder.FindAllStringSubmatch(text, -1)
 
 	replaced := map[string]bool{}
 	for _, image := range images {
 		if want, got := 4, len(image); want != got {
-			p.log.Errorf("Unable to parse \\includegraphics parameters")
+			p.warn(report, SeverityError, WarningUnreadableFile, element, "Unable to parse \\includegraphics parameters")
 			continue
 		}
 
@@ -1073,15 +1505,15 @@ func (p *ProblemLoader) uploadImagesFromLatex(ctx context.Context, path, text st
 			continue
 		}
 
-		data, err := os.ReadFile(filepath.Join(path, name))
+		data, err := fs.ReadFile(fsys, fsName(dir, name))
 		if err != nil {
-			p.log.Errorf("Unable to read image %#v: %v", name, err)
+			p.warn(report, SeverityError, WarningMissingFile, element, "Unable to read image %#v: %v", name, err)
 			continue
 		}
 
 		asset, err := p.assets.UploadAsset(ctx, &assetpb.UploadAssetInput{Name: name, Data: data})
 		if err != nil {
-			p.log.Errorf("Unable to upload image %#v: %v", name, err)
+			p.warn(report, SeverityError, WarningUploadFailed, element, "Unable to upload image %#v: %v", name, err)
 			continue
 		}
 
@@ -1161,10 +1593,10 @@ func (p *ProblemLoader) mapGroupToIndex(testset SpecificationTestset) map[string
 }
 
 // uploadFile to eolymp's blob storage, used to upload test data
-func (p *ProblemLoader) uploadFile(ctx context.Context, path string) (string, error) {
-	name := filepath.Base(path)
+func (p *ProblemLoader) uploadFile(ctx context.Context, fsys fs.FS, filename string) (string, error) {
+	name := path.Base(filename)
 
-	hash, err := p.hashFile(path)
+	hash, err := p.hashFile(fsys, filename)
 	if err != nil {
 		return "", err
 	}
@@ -1178,7 +1610,7 @@ func (p *ProblemLoader) uploadFile(ctx context.Context, path string) (string, er
 	}
 
 	// upload file
-	file, err := os.Open(path)
+	file, err := fsys.Open(filename)
 	if err != nil {
 		return "", fmt.Errorf("unable to open file: %w", err)
 	}
@@ -1189,7 +1621,7 @@ func (p *ProblemLoader) uploadFile(ctx context.Context, path string) (string, er
 	chunk := make([]byte, objectChunkSize)
 	reader := crlf.NewReader(file)
 
-	upload, err := p.assets.StartMultipartUpload(ctx, &assetpb.StartMultipartUploadInput{Name: filepath.Base(path), Type: "text/plain", Keys: []string{key}})
+	upload, err := p.assets.StartMultipartUpload(ctx, &assetpb.StartMultipartUploadInput{Name: name, Type: "text/plain", Keys: []string{key}})
 	if err != nil {
 		return "", fmt.Errorf("unable to start multipart upload: %w", err)
 	}
@@ -1240,8 +1672,8 @@ func (p *ProblemLoader) uploadFile(ctx context.Context, path string) (string, er
 	return out.GetAssetUrl(), nil
 }
 
-func (p *ProblemLoader) hashFile(path string) (string, error) {
-	file, err := os.Open(path)
+func (p *ProblemLoader) hashFile(fsys fs.FS, filename string) (string, error) {
+	file, err := fsys.Open(filename)
 	if err != nil {
 		return "", err
 	}
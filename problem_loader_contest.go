@@ -0,0 +1,142 @@
+package polygon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
+	"golang.org/x/sync/errgroup"
+)
+
+// ContestError lists problems which could not be fetched, keyed by problem letter.
+type ContestError map[string]error
+
+func (e ContestError) Error() string {
+	letters := make([]string, 0, len(e))
+	for letter := range e {
+		letters = append(letters, letter)
+	}
+
+	sort.Strings(letters)
+
+	var messages []string
+	for _, letter := range letters {
+		messages = append(messages, fmt.Sprintf("problem %v: %v", letter, e[letter]))
+	}
+
+	return fmt.Sprintf("unable to fetch %v problem(s): %v", len(e), strings.Join(messages, "; "))
+}
+
+// FetchContest downloads, parses and normalizes all problems of the contest, snapshots and import reports are keyed by
+// problem letter.
+//
+// The link must be a valid url similar to the one used by Fetch, but with contestId query parameter instead of
+// problemId, e.g. polygon://api-key:api-secret@/?contestId=123
+//
+// Problems are fetched independently, a failure to fetch one of them does not stop others. In this case snapshots of
+// successfully fetched problems are returned along with ContestError describing failed ones.
+//
+// Credentials from the link are never included into returned errors or log messages.
+func (p *ProblemLoader) FetchContest(ctx context.Context, link string) (map[string]*atlaspb.Snapshot, map[string]*ImportReport, error) {
+	snapshots, reports, err := p.fetchContestLink(ctx, link)
+
+	secrets := linkSecrets(link)
+
+	var failures ContestError
+	if errors.As(err, &failures) {
+		for letter, failure := range failures {
+			failures[letter] = redactError(failure, secrets...)
+		}
+
+		return snapshots, reports, failures
+	}
+
+	return snapshots, reports, redactError(err, secrets...)
+}
+
+func (p *ProblemLoader) fetchContestLink(ctx context.Context, link string) (map[string]*atlaspb.Snapshot, map[string]*ImportReport, error) {
+	origin, err := url.Parse(link)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid contest origin: %w", err)
+	}
+
+	if origin.Scheme != "polygon" {
+		return nil, nil, fmt.Errorf("invalid contest origin: schema %#v is not supported", origin.Scheme)
+	}
+
+	cid, err := strconv.ParseInt(origin.Query().Get("contestId"), 10, 32)
+	if err != nil {
+		return nil, nil, errors.New("invalid contest origin: query parameter contestId must be a valid integer")
+	}
+
+	policy, err := p.policy.override(origin.Query())
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid contest origin: %w", err)
+	}
+
+	poly, err := p.client(origin)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return p.fetchContest(ctx, poly, int(cid), policy)
+}
+
+func (p *ProblemLoader) fetchContest(ctx context.Context, poly *Client, contest int, policy PackagePolicy) (map[string]*atlaspb.Snapshot, map[string]*ImportReport, error) {
+	problems, err := poly.ContestProblems(ctx, ContestProblemsInput{ContestID: contest})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list contest problems: %w", err)
+	}
+
+	var lock sync.Mutex
+
+	snapshots := map[string]*atlaspb.Snapshot{}
+	reports := map[string]*ImportReport{}
+	failures := ContestError{}
+
+	// errors are collected per problem, so group is only used to limit concurrency
+	eg := errgroup.Group{}
+	eg.SetLimit(max(p.limit, 1))
+
+	for letter, problem := range problems {
+		if problem.Deleted {
+			p.log.Printf("Skipping problem %v (#%v) because it is deleted", letter, problem.ID)
+			continue
+		}
+
+		eg.Go(func() error {
+			p.log.Printf("Fetching problem %v (#%v)", letter, problem.ID)
+
+			snapshot, report, err := p.fetch(ctx, func(ctx context.Context, path string) error {
+				return p.downloadByID(ctx, path, poly, problem.ID, policy, "")
+			})
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			if err != nil {
+				p.log.Errorf("Unable to fetch problem %v (#%v): %v", letter, problem.ID, err)
+				failures[letter] = err
+				return nil
+			}
+
+			snapshots[letter] = snapshot
+			reports[letter] = report
+			return nil
+		})
+	}
+
+	_ = eg.Wait()
+
+	if len(failures) > 0 {
+		return snapshots, reports, failures
+	}
+
+	return snapshots, reports, nil
+}
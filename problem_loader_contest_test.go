@@ -0,0 +1,113 @@
+package polygon
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// zipDir packs directory into a zip archive, it's used to mimic polygon packages
+func zipDir(t *testing.T, dir string) []byte {
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		file, err := writer.Create(filepath.ToSlash(name))
+		if err != nil {
+			return err
+		}
+
+		_, err = file.Write(data)
+		return err
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func TestProblemLoader_FetchContest(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t})
+
+	poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+		switch method {
+		case "contest.problems":
+			return map[string]any{
+				"A": map[string]any{"id": 1, "name": "topics"},
+				"B": map[string]any{"id": 2, "name": "broken"},
+				"C": map[string]any{"id": 3, "name": "solutions"},
+				"D": map[string]any{"id": 4, "name": "deleted", "deleted": true},
+			}, nil
+		case "problem.packages":
+			return []any{map[string]any{"id": 10, "state": "READY", "type": "windows"}}, nil
+		case "problem.package":
+			switch params["problemId"] {
+			case "1":
+				return zipDir(t, ".testdata/01-topics"), nil
+			case "3":
+				return zipDir(t, ".testdata/06-solutions"), nil
+			}
+		}
+
+		return nil, errors.New("no such problem")
+	})
+
+	got, reports, err := loader.fetchContest(ctx, poly, 100, defaultPackagePolicy)
+
+	var failures ContestError
+	if !errors.As(err, &failures) {
+		t.Fatalf("Fetch must return ContestError, got %v instead", err)
+	}
+
+	if _, ok := failures["B"]; !ok || len(failures) != 1 {
+		t.Errorf("Only problem B must fail, got %v instead", failures)
+	}
+
+	var letters []string
+	for letter := range got {
+		letters = append(letters, letter)
+	}
+
+	sort.Strings(letters)
+
+	if want := []string{"A", "C"}; !cmp.Equal(want, letters) {
+		t.Errorf("Fetched problems do not match:\n%s", cmp.Diff(want, letters))
+	}
+
+	if len(got["C"].GetSolutions()) == 0 {
+		t.Errorf("Problem C must have solutions")
+	}
+
+	if reports["A"] == nil || reports["C"] == nil {
+		t.Errorf("Import reports must be returned for fetched problems")
+	}
+}
@@ -0,0 +1,231 @@
+package polygon
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrArchiveTooLarge is returned when problem archive exceeds size set by UseMaxArchiveSize.
+var ErrArchiveTooLarge = errors.New("problem archive is too large")
+
+// ErrChecksumMismatch is returned when problem archive does not match checksum given in the link.
+var ErrChecksumMismatch = errors.New("problem archive checksum does not match")
+
+// progressInterval is the minimal time between two progress reports
+const progressInterval = time.Second
+
+// Progress describes state of the problem archive download.
+type Progress struct {
+	Downloaded int64         // number of bytes saved so far
+	Total      int64         // size of the archive in bytes, -1 if unknown
+	Rate       float64       // average download rate in bytes per second
+	Elapsed    time.Duration // time since download has started
+	Checksum   string        // hex encoded SHA-256 of the archive, set in the final report only
+}
+
+// transfer keeps state of the archive download between resume attempts
+type transfer struct {
+	file     *os.File
+	hash     hash.Hash
+	written  int64
+	total    int64
+	start    time.Time
+	reported time.Time
+}
+
+// save streams problem archive into problem.zip, open is called to start download and to resume it from the given
+// offset after transient failures, checksum is expected hex encoded SHA-256 of the archive, empty if unknown
+func (p *ProblemLoader) save(ctx context.Context, path, checksum string, open func(ctx context.Context, offset int64) (*Download, error)) error {
+	file, err := os.OpenFile(filepath.Join(path, "problem.zip"), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to create problem archive: %w", err)
+	}
+
+	defer file.Close()
+
+	t := &transfer{file: file, hash: sha256.New(), total: -1, start: time.Now()}
+
+	for attempt := 0; ; attempt++ {
+		err := p.receive(ctx, t, open)
+		if err == nil {
+			break
+		}
+
+		if attempt >= p.resume || ctx.Err() != nil || !transient(err) {
+			return err
+		}
+
+		p.log.Printf("Download is interrupted after %v bytes, resuming: %v", t.written, err)
+	}
+
+	if t.total >= 0 && t.written != t.total {
+		return fmt.Errorf("problem archive is incomplete: received %v out of %v bytes", t.written, t.total)
+	}
+
+	p.log.Printf("Problem archive is %v bytes, SHA-256 checksum %x", t.written, t.hash.Sum(nil))
+
+	if err := t.verify(checksum); err != nil {
+		return err
+	}
+
+	p.report(t, true)
+
+	return nil
+}
+
+// load checks problem.zip which is not downloaded (e.g. loaded from cache) the same way as downloaded archives and
+// reports its checksum
+func (p *ProblemLoader) load(path, checksum string) error {
+	file, err := os.Open(filepath.Join(path, "problem.zip"))
+	if err != nil {
+		return fmt.Errorf("unable to open problem archive: %w", err)
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("unable to open problem archive: %w", err)
+	}
+
+	if p.maxArchive > 0 && info.Size() > p.maxArchive {
+		return fmt.Errorf("%w: archive is %v bytes, limit is %v bytes", ErrArchiveTooLarge, info.Size(), p.maxArchive)
+	}
+
+	t := &transfer{file: file, hash: sha256.New(), total: info.Size(), start: time.Now()}
+
+	if t.written, err = io.Copy(t.hash, file); err != nil {
+		return fmt.Errorf("unable to read problem archive: %w", err)
+	}
+
+	if err := t.verify(checksum); err != nil {
+		return err
+	}
+
+	p.report(t, true)
+
+	return nil
+}
+
+// receive opens archive stream at the current offset and copies it into the file
+func (p *ProblemLoader) receive(ctx context.Context, t *transfer, open func(ctx context.Context, offset int64) (*Download, error)) error {
+	src, err := open(ctx, t.written)
+	if err != nil {
+		return err
+	}
+
+	defer src.Close()
+
+	if src.Offset != t.written {
+		if src.Offset != 0 {
+			return fmt.Errorf("server streams archive from byte %v, but %v was requested", src.Offset, t.written)
+		}
+
+		// server does not support ranges, start over
+		if err := t.reset(); err != nil {
+			return fmt.Errorf("unable to truncate problem archive: %w", err)
+		}
+	}
+
+	if src.Size >= 0 {
+		t.total = src.Size
+	}
+
+	if p.maxArchive > 0 && t.total > p.maxArchive {
+		return fmt.Errorf("%w: archive is %v bytes, limit is %v bytes", ErrArchiveTooLarge, t.total, p.maxArchive)
+	}
+
+	buffer := make([]byte, 32*1024)
+
+	for {
+		n, err := src.Read(buffer)
+		if n > 0 {
+			if p.maxArchive > 0 && t.written+int64(n) > p.maxArchive {
+				return fmt.Errorf("%w: limit is %v bytes", ErrArchiveTooLarge, p.maxArchive)
+			}
+
+			if _, err := t.file.Write(buffer[:n]); err != nil {
+				return fmt.Errorf("unable to write problem archive: %w", err)
+			}
+
+			t.hash.Write(buffer[:n])
+			t.written += int64(n)
+
+			p.report(t, false)
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// report download progress to the callback, unless final it's throttled to one call per progressInterval
+func (p *ProblemLoader) report(t *transfer, final bool) {
+	if p.progress == nil {
+		return
+	}
+
+	now := time.Now()
+	if !final && now.Sub(t.reported) < progressInterval {
+		return
+	}
+
+	t.reported = now
+
+	elapsed := now.Sub(t.start)
+
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(t.written) / elapsed.Seconds()
+	}
+
+	progress := Progress{Downloaded: t.written, Total: t.total, Rate: rate, Elapsed: elapsed}
+	if final {
+		progress.Checksum = fmt.Sprintf("%x", t.hash.Sum(nil))
+	}
+
+	p.progress(progress)
+}
+
+// verify makes sure archive is a readable zip (its central directory is intact) and it matches expected checksum
+func (t *transfer) verify(checksum string) error {
+	if _, err := zip.NewReader(t.file, t.written); err != nil {
+		return fmt.Errorf("problem archive is not a valid zip: %w", err)
+	}
+
+	if got := fmt.Sprintf("%x", t.hash.Sum(nil)); checksum != "" && !strings.EqualFold(checksum, got) {
+		return fmt.Errorf("%w: expected %v, got %v", ErrChecksumMismatch, checksum, got)
+	}
+
+	return nil
+}
+
+// reset discards everything downloaded so far
+func (t *transfer) reset() error {
+	if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if err := t.file.Truncate(0); err != nil {
+		return err
+	}
+
+	t.hash.Reset()
+	t.written = 0
+
+	return nil
+}
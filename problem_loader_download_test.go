@@ -0,0 +1,193 @@
+package polygon
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// archiveServer serves archive honoring range requests, the first response is interrupted after cut bytes
+func archiveServer(t *testing.T, archive string, cut int, ranges bool) (*httptest.Server, *[]string) {
+	var requested []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.Header.Get("Range"))
+
+		w.Header().Set("Content-Type", "application/zip")
+
+		if !ranges {
+			r.Header.Del("Range")
+		}
+
+		if len(requested) == 1 && cut > 0 {
+			w.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+			_, _ = w.Write([]byte(archive[:cut]))
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+
+		http.ServeContent(w, r, "problem.zip", time.Time{}, strings.NewReader(archive))
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return srv, &requested
+}
+
+// zipArchive returns content of zip archive with a single incompressible file of the given size
+func zipArchive(t *testing.T, size int) string {
+	data, err := os.ReadFile(filepath.Join(writeZip(t, zipEntry{name: "tests/01", data: noise(size)}), "problem.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestProblemLoader_DownloadResume(t *testing.T) {
+	ctx := context.Background()
+	archive := zipArchive(t, 100000)
+
+	t.Run("resume from the last byte", func(t *testing.T) {
+		srv, requested := archiveServer(t, archive, 40000, true)
+
+		var progress []Progress
+		loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t}, UseDownloadProgress(func(p Progress) {
+			progress = append(progress, p)
+		}))
+
+		link, _ := url.Parse(srv.URL)
+		path := t.TempDir()
+
+		if err := loader.downloadByLink(ctx, path, link); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(path, "problem.zip"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != archive {
+			t.Errorf("Downloaded archive does not match, got %v bytes out of %v", len(data), len(archive))
+		}
+
+		if want, got := "bytes=40000-", (*requested)[len(*requested)-1]; want != got {
+			t.Errorf("Download must be resumed: want range %v, got %v", want, got)
+		}
+
+		if len(progress) == 0 {
+			t.Fatal("Progress must be reported")
+		}
+
+		last := progress[len(progress)-1]
+		if last.Downloaded != int64(len(archive)) || last.Total != int64(len(archive)) {
+			t.Errorf("Final progress must report complete download, got %+v", last)
+		}
+
+		if want, got := fmt.Sprintf("%x", sha256.Sum256([]byte(archive))), last.Checksum; want != got {
+			t.Errorf("Checksum of resumed download does not match: want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("restart when range is not supported", func(t *testing.T) {
+		srv, _ := archiveServer(t, archive, 40000, false)
+
+		loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t})
+
+		link, _ := url.Parse(srv.URL)
+		path := t.TempDir()
+
+		if err := loader.downloadByLink(ctx, path, link); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(path, "problem.zip"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != archive {
+			t.Errorf("Downloaded archive does not match, got %v bytes out of %v", len(data), len(archive))
+		}
+	})
+
+	t.Run("resume disabled", func(t *testing.T) {
+		srv, _ := archiveServer(t, archive, 40000, true)
+
+		loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t}, UseDownloadResume(0))
+
+		link, _ := url.Parse(srv.URL)
+
+		if err := loader.downloadByLink(ctx, t.TempDir(), link); err == nil {
+			t.Error("Interrupted download must fail when resume is disabled")
+		}
+	})
+}
+
+func TestProblemLoader_MaxArchiveSize(t *testing.T) {
+	ctx := context.Background()
+
+	srv, _ := archiveServer(t, zipArchive(t, 100000), 0, true)
+
+	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t}, UseMaxArchiveSize(1000))
+
+	link, _ := url.Parse(srv.URL)
+
+	if err := loader.downloadByLink(ctx, t.TempDir(), link); !errors.Is(err, ErrArchiveTooLarge) {
+		t.Errorf("Download must fail with ErrArchiveTooLarge, got %v instead", err)
+	}
+}
+
+func TestProblemLoader_DownloadChecksum(t *testing.T) {
+	ctx := context.Background()
+
+	archive := zipArchive(t, 1000)
+	checksum := fmt.Sprintf("%x", sha256.Sum256([]byte(archive)))
+
+	t.Run("matching checksum", func(t *testing.T) {
+		srv, _ := archiveServer(t, archive, 0, true)
+
+		loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t})
+
+		link, _ := url.Parse(srv.URL + "/?checksum=" + strings.ToUpper(checksum))
+
+		if err := loader.downloadByLink(ctx, t.TempDir(), link); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		srv, _ := archiveServer(t, archive, 0, true)
+
+		loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t})
+
+		link, _ := url.Parse(srv.URL + "/?checksum=" + strings.Repeat("0", 64))
+
+		if err := loader.downloadByLink(ctx, t.TempDir(), link); !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("Download must fail with ErrChecksumMismatch, got %v instead", err)
+		}
+	})
+
+	t.Run("invalid zip", func(t *testing.T) {
+		srv, _ := archiveServer(t, archive[:len(archive)-10], 0, true)
+
+		loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t})
+
+		link, _ := url.Parse(srv.URL)
+
+		if err := loader.downloadByLink(ctx, t.TempDir(), link); err == nil {
+			t.Errorf("Download must fail if archive is not a valid zip")
+		}
+	})
+}
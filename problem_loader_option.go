@@ -0,0 +1,158 @@
+package polygon
+
+// UseFreshPackages makes loader build a new package when there is no ready package for the current problem revision,
+// instead of importing an outdated one. If the package is being built already (e.g. by a concurrent import), loader
+// waits for it. Set verify to run all solutions on all tests while building the package.
+func UseFreshPackages(verify bool) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.policy.Current = true
+		p.policy.Verify = verify
+	}
+}
+
+// UsePackagePolicy sets default policy to pick package for import, it can be overridden by the problem link.
+func UsePackagePolicy(policy PackagePolicy) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.policy = policy
+	}
+}
+
+// UseContestConcurrency sets number of problems FetchContest downloads and converts in parallel.
+func UseContestConcurrency(limit int) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.limit = limit
+	}
+}
+
+// UseDownloadProgress sets callback to receive problem archive download progress, the callback is called at most once
+// a second and once more when download is complete. The final report carries SHA-256 checksum of the archive, which
+// can be used to verify or record the imported package, archives loaded from cache are reported with a final report
+// only.
+func UseDownloadProgress(progress func(Progress)) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.progress = progress
+	}
+}
+
+// UseMaxArchiveSize aborts download of problem archives larger than size bytes with ErrArchiveTooLarge.
+func UseMaxArchiveSize(size int64) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.maxArchive = size
+	}
+}
+
+// UseDownloadResume sets number of attempts to resume problem archive download after a network failure, downloads
+// are resumed from the last received byte if server supports range requests and restarted otherwise.
+func UseDownloadResume(attempts int) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.resume = attempts
+	}
+}
+
+// UsePackageCache makes loader keep downloaded packages in the cache and skip download of cached packages. Cached
+// archives are checked against UseMaxArchiveSize as well.
+func UsePackageCache(cache PackageCache) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.cache = cache
+	}
+}
+
+// UseSourceResolver registers resolver for links with the given scheme, it replaces resolver registered before.
+func UseSourceResolver(scheme string, resolver SourceResolver) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.resolvers[scheme] = resolver
+	}
+}
+
+// UseFileSource enables file:// links to local zip archives or extracted packages. Links are restricted to the root
+// directory, unless root is empty.
+func UseFileSource(root string) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.resolvers["file"] = p.resolveFile(root)
+	}
+}
+
+// UseHTTPSource enables https:// links to zip archives on any host. Tokens are keyed by host (with port, if link has
+// one), the token is sent as a bearer token only to its host, archives on other hosts are downloaded without
+// authorization. Links to polygon website keep working as before.
+func UseHTTPSource(tokens map[string]string) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.resolvers["https"] = p.resolveHTTP(tokens)
+	}
+}
+
+// UsePolygonHosts sets hosts of polygon instances loader is allowed to access, both by polygon:// links and by links to
+// polygon website. Hosts are accessed over https, unless host is given with a scheme, e.g. http://localhost:8080.
+func UsePolygonHosts(hosts ...string) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.hosts = hosts
+	}
+}
+
+// UseLoaderHTTPClient sets HTTP client for all requests made by loader, including polygon API requests and archive
+// downloads.
+func UseLoaderHTTPClient(hc httpClient) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.http = hc
+	}
+}
+
+// UseClientFactory sets function to create polygon API clients, the loader passes options with HTTP client and base
+// URL, the factory may add its own options (e.g. UseRetry or UseRateLimit). The loader calls factory once for each
+// key and host and reuses created client for all following fetches.
+func UseClientFactory(factory func(key, secret string, opts ...func(*Client)) *Client) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.factory = factory
+	}
+}
+
+// UseLoaderRateLimit throttles polygon API requests made by loader, see UseRateLimit. Limits are applied per key and
+// host, so concurrent fetches (including FetchContest) with the same key share them.
+func UseLoaderRateLimit(rate float64, burst, inflight int) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.throttle = UseRateLimit(rate, burst, inflight)
+	}
+}
+
+// UseUnpackLimits sets limits on size and number of files unpacked from problem archive, when a limit is exceeded
+// loader fails with UnpackError. The limits replace defaults (8 GiB in total, 2 GiB per file and 100000 entries), zero
+// value of a limit disables it. Symbolic links and duplicate entries are rejected regardless of limits.
+func UseUnpackLimits(limits UnpackLimits) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.unpackLimits = limits
+	}
+}
+
+// UseDirectArchive makes loader read problem package straight from the downloaded archive instead of unpacking it into
+// the workspace, it saves disk space at the cost of slower reads. Archive entries are checked against unpack limits
+// the same way. Note, the archive itself is still downloaded into the workspace, to convert package without any disk
+// access call SnapshotFS with *zip.Reader over the archive kept in memory.
+func UseDirectArchive() func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.direct = true
+	}
+}
+
+// UseWorkspace sets directory where loader creates workspaces to download and unpack packages, by default system temp
+// directory is used.
+func UseWorkspace(root string) func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.workspace = root
+	}
+}
+
+// UseKeepFailedWorkspace makes loader keep workspace with downloaded and unpacked package when import fails, the
+// location is reported in WorkspaceError. Kept workspaces have to be removed by the caller.
+func UseKeepFailedWorkspace() func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.keep = true
+	}
+}
+
+// UseStrictImport makes loader fail instead of skipping package content it can not import (e.g. statement in unknown
+// language, unmapped runtime or failed upload). The error is StrictError listing all violations found in the package.
+func UseStrictImport() func(*ProblemLoader) {
+	return func(p *ProblemLoader) {
+		p.strict = true
+	}
+}
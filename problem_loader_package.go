@@ -0,0 +1,159 @@
+package polygon
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PackagePolicy defines which package is imported when problem is fetched by ID.
+//
+// The policy can be overridden by query parameters of the problem link:
+//   - type=linux,windows sets Types
+//   - revision=12 sets Revision
+//   - revision=latest sets Latest
+//   - revision=current sets Current
+//   - verify=true sets Verify
+type PackagePolicy struct {
+	Types    []string // acceptable package types (windows, linux or standard) in order of preference
+	Revision int      // import package for exact problem revision, 0 means any revision
+	Latest   bool     // import package only if it's built for the latest revision among ready packages
+	Current  bool     // import package for the current problem revision, build a new one if there is none
+	Verify   bool     // verify solutions when building a new package
+}
+
+// defaultPackagePolicy imports windows package, so we can use generated tests
+var defaultPackagePolicy = PackagePolicy{Types: []string{"windows"}}
+
+var packageTypes = []string{"windows", "linux", "standard"}
+
+// override policy with query parameters of the problem link
+func (policy PackagePolicy) override(query url.Values) (PackagePolicy, error) {
+	if query.Has("type") {
+		policy.Types = nil
+
+		for _, kind := range strings.Split(query.Get("type"), ",") {
+			if !slices.Contains(packageTypes, kind) {
+				return policy, fmt.Errorf("query parameter type must be one of %v, got %#v", strings.Join(packageTypes, ", "), kind)
+			}
+
+			policy.Types = append(policy.Types, kind)
+		}
+	}
+
+	if query.Has("revision") {
+		policy.Revision, policy.Latest, policy.Current = 0, false, false
+
+		switch value := query.Get("revision"); value {
+		case "latest":
+			policy.Latest = true
+		case "current":
+			policy.Current = true
+		default:
+			revision, err := strconv.Atoi(value)
+			if err != nil || revision <= 0 {
+				return policy, fmt.Errorf("query parameter revision must be a positive integer, latest or current, got %#v", value)
+			}
+
+			policy.Revision = revision
+		}
+	}
+
+	if query.Has("verify") {
+		verify, err := strconv.ParseBool(query.Get("verify"))
+		if err != nil {
+			return policy, fmt.Errorf("query parameter verify must be a boolean, got %#v", query.Get("verify"))
+		}
+
+		policy.Verify = verify
+	}
+
+	return policy, nil
+}
+
+// accepts returns true if package type is acceptable by the policy
+func (policy PackagePolicy) accepts(pack Package) bool {
+	return slices.Contains(policy.types(), pack.Type)
+}
+
+func (policy PackagePolicy) types() []string {
+	if len(policy.Types) == 0 {
+		return defaultPackagePolicy.Types
+	}
+
+	return policy.Types
+}
+
+// pick ready package for the given revision (0 means any), preferred types go first, then newer packages
+func (policy PackagePolicy) pick(packages []Package, revision int) *Package {
+	for _, kind := range policy.types() {
+		var best *Package
+
+		for i := range packages {
+			pack := &packages[i]
+			if pack.Type != kind || pack.State != "READY" || (revision != 0 && pack.Revision != revision) {
+				continue
+			}
+
+			if best == nil || newerPackage(pack, best) {
+				best = pack
+			}
+		}
+
+		if best != nil {
+			return best
+		}
+	}
+
+	return nil
+}
+
+// building returns package of acceptable type which is being built for the given revision
+func (policy PackagePolicy) building(packages []Package, revision int) *Package {
+	for i := range packages {
+		pack := &packages[i]
+		if (pack.State == "PENDING" || pack.State == "RUNNING") && pack.Revision == revision && policy.accepts(*pack) {
+			return pack
+		}
+	}
+
+	return nil
+}
+
+// full returns true if policy requires full package (windows or linux), standard package is enough otherwise
+func (policy PackagePolicy) full() bool {
+	for _, kind := range policy.types() {
+		if kind != "standard" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// latest returns the latest revision among ready packages of acceptable types
+func (policy PackagePolicy) latest(packages []Package) int {
+	revision := 0
+	for _, pack := range packages {
+		if pack.State == "READY" && policy.accepts(pack) {
+			revision = max(revision, pack.Revision)
+		}
+	}
+
+	return revision
+}
+
+// newerPackage returns true if package a is built for a later revision or later than package b
+func newerPackage(a, b *Package) bool {
+	if a.Revision != b.Revision {
+		return a.Revision > b.Revision
+	}
+
+	if a.CreationTimeSeconds != b.CreationTimeSeconds {
+		return a.CreationTimeSeconds > b.CreationTimeSeconds
+	}
+
+	return a.ID > b.ID
+}
@@ -0,0 +1,187 @@
+package polygon
+
+import (
+	"context"
+	"net/url"
+	"testing"
+)
+
+func TestPackagePolicy_Override(t *testing.T) {
+	tests := []struct {
+		query string
+		want  PackagePolicy
+		fail  bool
+	}{
+		{query: "", want: PackagePolicy{Types: []string{"windows"}}},
+		{query: "type=linux,windows", want: PackagePolicy{Types: []string{"linux", "windows"}}},
+		{query: "revision=12&type=linux", want: PackagePolicy{Types: []string{"linux"}, Revision: 12}},
+		{query: "revision=latest", want: PackagePolicy{Types: []string{"windows"}, Latest: true}},
+		{query: "revision=current&verify=true", want: PackagePolicy{Types: []string{"windows"}, Current: true, Verify: true}},
+		{query: "type=macos", fail: true},
+		{query: "revision=-1", fail: true},
+		{query: "verify=maybe", fail: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			query, _ := url.ParseQuery(tc.query)
+
+			got, err := defaultPackagePolicy.override(query)
+			if tc.fail {
+				if err == nil {
+					t.Errorf("Query %#v must be rejected", tc.query)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.Revision != tc.want.Revision || got.Latest != tc.want.Latest || got.Current != tc.want.Current ||
+				got.Verify != tc.want.Verify || len(got.Types) != len(tc.want.Types) {
+				t.Fatalf("Policy does not match: want %+v, got %+v", tc.want, got)
+			}
+
+			for i := range got.Types {
+				if got.Types[i] != tc.want.Types[i] {
+					t.Errorf("Policy types do not match: want %v, got %v", tc.want.Types, got.Types)
+				}
+			}
+		})
+	}
+}
+
+func TestProblemLoader_PickPackage(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t})
+
+	built := false
+
+	poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+		switch method {
+		case "problem.packages":
+			packages := []any{
+				map[string]any{"id": 1, "revision": 10, "creationTimeSeconds": 100, "state": "READY", "type": "windows"},
+				map[string]any{"id": 2, "revision": 12, "creationTimeSeconds": 200, "state": "READY", "type": "linux"},
+				map[string]any{"id": 3, "revision": 11, "creationTimeSeconds": 150, "state": "READY", "type": "windows"},
+				map[string]any{"id": 4, "revision": 13, "creationTimeSeconds": 300, "state": "FAILED", "type": "windows"},
+			}
+
+			if built {
+				packages = append(packages, map[string]any{"id": 5, "revision": 14, "creationTimeSeconds": 400, "state": "READY", "type": "windows"})
+			}
+
+			return packages, nil
+		case "problems.list":
+			return []any{map[string]any{"id": 123, "revision": 14}}, nil
+		case "problem.buildPackage":
+			built = true
+			return nil, nil
+		}
+
+		return nil, nil
+	})
+
+	tests := []struct {
+		name   string
+		policy PackagePolicy
+		want   int
+	}{
+		{name: "newest windows package", policy: PackagePolicy{}, want: 3},
+		{name: "preferred type", policy: PackagePolicy{Types: []string{"linux", "windows"}}, want: 2},
+		{name: "exact revision", policy: PackagePolicy{Revision: 10}, want: 1},
+		{name: "latest revision", policy: PackagePolicy{Types: []string{"windows", "linux"}, Latest: true}, want: 2},
+		{name: "build for current revision", policy: PackagePolicy{Current: true}, want: 5},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pack, err := loader.pickPackage(ctx, poly, 123, tc.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if pack.ID != tc.want {
+				t.Errorf("Picked package does not match: want #%v, got #%v", tc.want, pack.ID)
+			}
+		})
+	}
+
+	t.Run("exact revision of unavailable type", func(t *testing.T) {
+		if _, err := loader.pickPackage(ctx, poly, 123, PackagePolicy{Revision: 12}); err == nil {
+			t.Error("There is no windows package for revision 12, pickPackage must fail")
+		}
+	})
+}
+
+func TestProblemLoader_FreshPackage(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t})
+
+	t.Run("wait for package being built", func(t *testing.T) {
+		listed := 0
+
+		poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+			switch method {
+			case "problem.packages":
+				listed++
+
+				state := "RUNNING"
+				if listed > 1 {
+					state = "READY"
+				}
+
+				return []any{
+					map[string]any{"id": 1, "revision": 10, "state": "READY", "type": "windows"},
+					map[string]any{"id": 2, "revision": 14, "state": state, "type": "windows"},
+				}, nil
+			case "problems.list":
+				return []any{map[string]any{"id": 123, "revision": 14}}, nil
+			case "problem.buildPackage":
+				t.Error("Package must not be built while another build for the same revision is running")
+			}
+
+			return nil, nil
+		})
+
+		pack, err := loader.pickPackage(ctx, poly, 123, PackagePolicy{Current: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := 2, pack.ID; want != got {
+			t.Errorf("Picked package does not match: want #%v, got #%v", want, got)
+		}
+	})
+
+	t.Run("build standard package", func(t *testing.T) {
+		full := ""
+
+		poly := apiMock(t, func(method string, params map[string]string) (any, error) {
+			switch method {
+			case "problem.packages":
+				if full == "" {
+					return []any{}, nil
+				}
+
+				return []any{map[string]any{"id": 1, "revision": 14, "state": "READY", "type": "standard"}}, nil
+			case "problems.list":
+				return []any{map[string]any{"id": 123, "revision": 14}}, nil
+			case "problem.buildPackage":
+				full = params["full"]
+			}
+
+			return nil, nil
+		})
+
+		if _, err := loader.pickPackage(ctx, poly, 123, PackagePolicy{Types: []string{"standard"}, Current: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := "false", full; want != got {
+			t.Errorf("Standard package must be built without generated tests: want full=%v, got full=%v", want, got)
+		}
+	})
+}
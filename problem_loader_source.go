@@ -0,0 +1,163 @@
+package polygon
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SourceResolver fetches problem package referenced by the link into the workspace directory. The package can be
+// saved either as problem.zip archive, which is unpacked by the loader, or as already extracted files.
+type SourceResolver interface {
+	Resolve(ctx context.Context, link *url.URL, workspace string) error
+}
+
+// SourceResolverFunc is an adapter to use ordinary function as SourceResolver.
+type SourceResolverFunc func(ctx context.Context, link *url.URL, workspace string) error
+
+func (f SourceResolverFunc) Resolve(ctx context.Context, link *url.URL, workspace string) error {
+	return f(ctx, link, workspace)
+}
+
+// resolveFile copies local archive or extracted package, link is file:///path/to/problem.zip, if root is set the path
+// must be inside the root
+func (p *ProblemLoader) resolveFile(root string) SourceResolverFunc {
+	return func(ctx context.Context, link *url.URL, workspace string) error {
+		if link.Host != "" && link.Host != "localhost" {
+			return fmt.Errorf("invalid problem origin: host %#v is not supported", link.Host)
+		}
+
+		path, err := filepath.EvalSymlinks(filepath.FromSlash(link.Path))
+		if err != nil {
+			return fmt.Errorf("unable to read problem origin: %w", err)
+		}
+
+		if root != "" {
+			root, err := filepath.EvalSymlinks(root)
+			if err != nil {
+				return fmt.Errorf("unable to read source root: %w", err)
+			}
+
+			rel, err := filepath.Rel(root, path)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return fmt.Errorf("invalid problem origin: path %#v is outside of allowed directory", link.Path)
+			}
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("unable to read problem origin: %w", err)
+		}
+
+		if info.IsDir() {
+			return p.copyPackage(ctx, path, workspace)
+		}
+
+		if p.maxArchive > 0 && info.Size() > p.maxArchive {
+			return fmt.Errorf("%w: archive is %v bytes, limit is %v bytes", ErrArchiveTooLarge, info.Size(), p.maxArchive)
+		}
+
+		if err := copyFile(path, filepath.Join(workspace, "problem.zip")); err != nil {
+			return fmt.Errorf("unable to copy problem archive: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// copyPackage copies extracted package into the workspace, symbolic links are skipped
+func (p *ProblemLoader) copyPackage(ctx context.Context, src, dst string) error {
+	if _, err := os.Stat(filepath.Join(src, "problem.xml")); err != nil {
+		return fmt.Errorf("directory %#v does not contain problem.xml", filepath.Base(src))
+	}
+
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(filepath.Join(dst, name), 0700)
+		case d.Type().IsRegular():
+			return copyFile(path, filepath.Join(dst, name))
+		default:
+			p.log.Printf("Skipping %#v because it is not a regular file", name)
+			return nil
+		}
+	})
+}
+
+// resolveHTTP downloads zip archive by the link, bearer token is sent only to the hosts it's configured for, links to
+// polygon website are downloaded using login and password from the link
+func (p *ProblemLoader) resolveHTTP(tokens map[string]string) SourceResolverFunc {
+	return func(ctx context.Context, link *url.URL, workspace string) error {
+		if _, ok := p.polygonHost(link.Host); ok {
+			return p.resolvePolygonLink(ctx, link, workspace)
+		}
+
+		return p.downloadArchive(ctx, workspace, link, hostToken(tokens, link.Host))
+	}
+}
+
+// hostToken finds token configured for the host, host names are case-insensitive
+func hostToken(tokens map[string]string, host string) string {
+	for name, token := range tokens {
+		if strings.EqualFold(name, host) {
+			return token
+		}
+	}
+
+	return ""
+}
+
+// downloadArchive downloads zip archive using HTTP GET request
+func (p *ProblemLoader) downloadArchive(ctx context.Context, path string, link *url.URL, token string) error {
+	return p.save(ctx, path, "", func(ctx context.Context, offset int64) (*Download, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, link.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to compose HTTP request: %w", err)
+		}
+
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		}
+
+		resp, err := p.http.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("HTTP request has failed: %w", err)
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			resp.Body.Close()
+			return nil, fmt.Errorf("problem link %#v leads to a file which does not exist", link.Redacted())
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+			resp.Body.Close()
+			return nil, fmt.Errorf("problem link %#v requires valid credentials", link.Redacted())
+		case resp.StatusCode/100 != 2:
+			resp.Body.Close()
+			return nil, fmt.Errorf("problem link %#v is invalid: server response code is %v", link.Redacted(), resp.StatusCode)
+		}
+
+		return newDownload(resp), nil
+	})
+}
@@ -0,0 +1,185 @@
+package polygon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProblemLoader_FetchViaFile(t *testing.T) {
+	ctx := context.Background()
+
+	root := t.TempDir()
+	archive := filepath.Join(root, "problem.zip")
+
+	if err := os.WriteFile(archive, zipDir(t, ".testdata/01-topics"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	extracted, err := filepath.Abs(".testdata/01-topics")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("zip archive", func(t *testing.T) {
+		loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t}, UseFileSource(root))
+
+		snap, _, err := loader.Fetch(ctx, (&url.URL{Scheme: "file", Path: filepath.ToSlash(archive)}).String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(snap.GetProblem().GetTopics()) == 0 {
+			t.Errorf("Problem topics must be imported")
+		}
+	})
+
+	t.Run("zip archive without unpacking", func(t *testing.T) {
+		loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t}, UseFileSource(root), UseDirectArchive())
+
+		snap, _, err := loader.Fetch(ctx, (&url.URL{Scheme: "file", Path: filepath.ToSlash(archive)}).String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(snap.GetProblem().GetTopics()) == 0 {
+			t.Errorf("Problem topics must be imported")
+		}
+	})
+
+	t.Run("extracted package", func(t *testing.T) {
+		loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t}, UseFileSource(""))
+
+		snap, _, err := loader.Fetch(ctx, (&url.URL{Scheme: "file", Path: filepath.ToSlash(extracted)}).String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(snap.GetProblem().GetTopics()) == 0 {
+			t.Errorf("Problem topics must be imported")
+		}
+	})
+
+	t.Run("path outside of root", func(t *testing.T) {
+		loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t}, UseFileSource(root))
+
+		if _, _, err := loader.Fetch(ctx, (&url.URL{Scheme: "file", Path: filepath.ToSlash(extracted)}).String()); err == nil {
+			t.Error("Fetch must fail for paths outside of root directory")
+		}
+	})
+
+	t.Run("file links are disabled by default", func(t *testing.T) {
+		loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t})
+
+		if _, _, err := loader.Fetch(ctx, (&url.URL{Scheme: "file", Path: filepath.ToSlash(archive)}).String()); err == nil {
+			t.Error("Fetch must fail for file links unless they are enabled")
+		}
+	})
+}
+
+func TestProblemLoader_FetchViaResolver(t *testing.T) {
+	ctx := context.Background()
+
+	data := zipDir(t, ".testdata/01-topics")
+
+	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t}, UseSourceResolver("s3", SourceResolverFunc(func(ctx context.Context, link *url.URL, workspace string) error {
+		if want, got := "bucket", link.Host; want != got {
+			t.Errorf("Link host does not match: want %v, got %v", want, got)
+		}
+
+		return os.WriteFile(filepath.Join(workspace, "problem.zip"), data, 0600)
+	})))
+
+	snap, _, err := loader.Fetch(ctx, "s3://bucket/problem.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(snap.GetProblem().GetTopics()) == 0 {
+		t.Errorf("Problem topics must be imported")
+	}
+}
+
+func TestProblemLoader_DownloadArchive(t *testing.T) {
+	ctx := context.Background()
+
+	data := zipDir(t, ".testdata/01-topics")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write(data)
+	}))
+
+	defer srv.Close()
+
+	link, _ := url.Parse(srv.URL + "/problem.zip")
+	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t})
+
+	if err := loader.downloadArchive(ctx, t.TempDir(), link, "wrong-token"); err == nil {
+		t.Error("Download must fail with invalid token")
+	}
+
+	path := t.TempDir()
+	if err := loader.downloadArchive(ctx, path, link, "secret-token"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(path, "problem.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != len(data) {
+		t.Errorf("Downloaded archive does not match, got %v bytes out of %v", len(got), len(data))
+	}
+}
+
+func TestProblemLoader_FetchViaHTTPS(t *testing.T) {
+	ctx := context.Background()
+
+	data := zipDir(t, ".testdata/01-topics")
+
+	// archiveHost serves problem archive and records authorization header it receives
+	archiveHost := func(authorization *string) *httptest.Server {
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*authorization = r.Header.Get("Authorization")
+			_, _ = w.Write(data)
+		}))
+
+		t.Cleanup(srv.Close)
+
+		return srv
+	}
+
+	var trustedAuth, otherAuth string
+
+	trusted := archiveHost(&trustedAuth)
+	other := archiveHost(&otherAuth)
+
+	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t},
+		UseHTTPSource(map[string]string{trusted.Listener.Addr().String(): "secret-token"}),
+		UseLoaderHTTPClient(trusted.Client()),
+	)
+
+	for _, srv := range []*httptest.Server{trusted, other} {
+		if _, _, err := loader.Fetch(ctx, srv.URL+"/problem.zip"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if want, got := "Bearer secret-token", trustedAuth; want != got {
+		t.Errorf("Token must be sent to the configured host: want %#v, got %#v", want, got)
+	}
+
+	if otherAuth != "" {
+		t.Errorf("Token must not be sent to other hosts, got %#v", otherAuth)
+	}
+}
@@ -1,12 +1,24 @@
 package polygon
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"testing/fstest"
+	"time"
 
+	"github.com/eolymp/go-polygon/polygontest"
 	atlaspb "github.com/eolymp/go-sdk/eolymp/atlas"
 	ecmpb "github.com/eolymp/go-sdk/eolymp/ecm"
 	executorpb "github.com/eolymp/go-sdk/eolymp/executor"
@@ -37,7 +49,7 @@ func TestProblemLoader_FetchViaID(t *testing.T) {
 		RawQuery: "problemId=270574",
 	}
 
-	_, err := loader.Fetch(ctx, link.String())
+	_, _, err := loader.Fetch(ctx, link.String())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -64,7 +76,7 @@ func TestProblemLoader_FetchViaLink(t *testing.T) {
 		Path:   "/p8bWTsG/eolymp/example-a-plus-b-testdata",
 	}
 
-	_, err := loader.Fetch(ctx, link.String())
+	_, _, err := loader.Fetch(ctx, link.String())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -72,12 +84,462 @@ func TestProblemLoader_FetchViaLink(t *testing.T) {
 	// todo: make some assertions
 }
 
+func TestProblemLoader_FetchViaFake(t *testing.T) {
+	ctx := context.Background()
+
+	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t})
+
+	srv := polygontest.NewServer("key", "secret")
+	defer srv.Close()
+
+	srv.AddProblem(123, ".testdata/06-solutions")
+
+	fetch := func(poly *Client, id int) (*atlaspb.Snapshot, error) {
+		snap, _, err := loader.fetch(ctx, func(ctx context.Context, path string) error {
+			return loader.downloadByID(ctx, path, poly, id, defaultPackagePolicy, "")
+		})
+
+		return snap, err
+	}
+
+	t.Run("download package", func(t *testing.T) {
+		snap, err := fetch(New("key", "secret", UseBaseURL(srv.BaseURL())), 123)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := 10, len(snap.GetSolutions()); want != got {
+			t.Errorf("Number of solutions does not match: want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("retry failed download", func(t *testing.T) {
+		srv.Fail("problem.package", polygontest.Failure{Code: http.StatusBadGateway})
+
+		before := srv.Calls("problem.package")
+
+		if _, err := fetch(New("key", "secret", UseBaseURL(srv.BaseURL()), UseRetry(1, time.Millisecond)), 123); err != nil {
+			t.Fatal(err)
+		}
+
+		if want, got := 2, srv.Calls("problem.package")-before; want != got {
+			t.Errorf("Number of download attempts does not match: want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		if _, err := fetch(New("key", "wrong-secret", UseBaseURL(srv.BaseURL())), 123); !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("Fetch must fail with ErrUnauthorized, got %v instead", err)
+		}
+	})
+
+	t.Run("unknown problem", func(t *testing.T) {
+		if _, err := fetch(New("key", "secret", UseBaseURL(srv.BaseURL())), 404); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Fetch must fail with ErrNotFound, got %v instead", err)
+		}
+	})
+}
+
+func TestProblemLoader_FetchViaMirror(t *testing.T) {
+	ctx := context.Background()
+
+	srv := polygontest.NewServer("key", "secret")
+	defer srv.Close()
+
+	srv.AddProblem(123, ".testdata/01-topics")
+
+	host := srv.Listener.Addr().String()
+
+	web := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("login") != "user" || r.PostForm.Get("password") != "pass" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		_, _ = w.Write(zipDir(t, ".testdata/01-topics"))
+	}))
+
+	defer web.Close()
+
+	t.Run("polygon link with allowed host", func(t *testing.T) {
+		loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t}, UsePolygonHosts(srv.URL))
+
+		snap, _, err := loader.Fetch(ctx, "polygon://key:secret@"+host+"/?problemId=123")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(snap.GetProblem().GetTopics()) == 0 {
+			t.Errorf("Problem topics must be imported")
+		}
+	})
+
+	t.Run("polygon link with unknown host", func(t *testing.T) {
+		loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t})
+
+		before := srv.Calls("problem.packages")
+
+		if _, _, err := loader.Fetch(ctx, "polygon://key:secret@"+host+"/?problemId=123"); err == nil {
+			t.Errorf("Fetch must fail for hosts which are not allowed")
+		}
+
+		if want, got := 0, srv.Calls("problem.packages")-before; want != got {
+			t.Errorf("Hosts which are not allowed must not be accessed, got %v calls", got)
+		}
+	})
+
+	t.Run("polygon link without host when default host is not allowed", func(t *testing.T) {
+		requests := 0
+
+		loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t},
+			UsePolygonHosts(srv.URL),
+			UseLoaderHTTPClient(httpClientFunc(func(req *http.Request) (*http.Response, error) {
+				requests++
+				return http.DefaultClient.Do(req)
+			})),
+		)
+
+		if _, _, err := loader.Fetch(ctx, "polygon://key:secret@/?problemId=123"); err == nil {
+			t.Errorf("Fetch must fail if default host is not allowed")
+		}
+
+		if requests != 0 {
+			t.Errorf("Hosts which are not allowed must not be accessed, got %v requests", requests)
+		}
+	})
+
+	t.Run("website link with allowed host", func(t *testing.T) {
+		loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t}, UsePolygonHosts(web.URL))
+
+		link := strings.Replace(web.URL, "http://", "http://user:pass@", 1) + "/p/eolymp/problem"
+
+		if _, _, err := loader.Fetch(ctx, link); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestProblemLoader_HTTPClient(t *testing.T) {
+	ctx := context.Background()
+
+	srv := polygontest.NewServer("key", "secret")
+	defer srv.Close()
+
+	srv.AddProblem(123, ".testdata/01-topics")
+
+	web := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		_, _ = w.Write(zipDir(t, ".testdata/01-topics"))
+	}))
+
+	defer web.Close()
+
+	var requests []string
+	var keys []string
+
+	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t},
+		UsePolygonHosts(srv.URL, web.URL),
+		UseLoaderHTTPClient(httpClientFunc(func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req.URL.Path)
+			return http.DefaultClient.Do(req)
+		})),
+		UseClientFactory(func(key, secret string, opts ...func(*Client)) *Client {
+			keys = append(keys, key)
+			return New(key, secret, append(opts, UseRetry(1, time.Millisecond))...)
+		}),
+	)
+
+	if _, _, err := loader.Fetch(ctx, "polygon://key:secret@"+srv.Listener.Addr().String()+"/?problemId=123"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := loader.Fetch(ctx, web.URL+"/p/eolymp/problem"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"/api/problem.packages", "/api/problem.package", "/p/eolymp/problem"}
+	if !cmp.Equal(want, requests) {
+		t.Errorf("All requests must be made with loader's HTTP client:\n%s", cmp.Diff(want, requests))
+	}
+
+	if want := []string{"key"}; !cmp.Equal(want, keys) {
+		t.Errorf("API client must be created by the factory:\n%s", cmp.Diff(want, keys))
+	}
+}
+
+func TestProblemLoader_RateLimit(t *testing.T) {
+	ctx := context.Background()
+
+	srv := polygontest.NewServer("key", "secret")
+	defer srv.Close()
+
+	srv.AddProblem(123, ".testdata/01-topics")
+	srv.AddProblem(456, ".testdata/01-topics")
+
+	var current, peak atomic.Int32
+	var created atomic.Int32
+
+	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t},
+		UsePolygonHosts(srv.URL),
+		UseLoaderRateLimit(0, 0, 1),
+		UseLoaderHTTPClient(httpClientFunc(func(req *http.Request) (*http.Response, error) {
+			n := current.Add(1)
+			defer current.Add(-1)
+
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+
+			return http.DefaultClient.Do(req)
+		})),
+		UseClientFactory(func(key, secret string, opts ...func(*Client)) *Client {
+			created.Add(1)
+			return New(key, secret, opts...)
+		}),
+	)
+
+	wg := sync.WaitGroup{}
+	for _, id := range []string{"123", "456"} {
+		wg.Go(func() {
+			if _, _, err := loader.Fetch(ctx, "polygon://key:secret@"+srv.Listener.Addr().String()+"/?problemId="+id); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	if got := peak.Load(); got > 1 {
+		t.Errorf("Concurrent fetches with the same key must share rate limit, got %v requests in flight", got)
+	}
+
+	if want, got := int32(1), created.Load(); want != got {
+		t.Errorf("API client must be reused for the same key: want %v clients, got %v", want, got)
+	}
+}
+
+func TestProblemLoader_SnapshotFS(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t})
+
+	for _, dir := range []string{".testdata/03-test-scoring-with-points", ".testdata/06-solutions", ".testdata/20-checker-tests"} {
+		t.Run(dir, func(t *testing.T) {
+			want, _, err := loader.Snapshot(ctx, dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data := zipDir(t, dir)
+
+			archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, _, err := loader.SnapshotFS(ctx, archive)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(want.GetSolutions(), got.GetSolutions(), opts...) {
+				t.Errorf("Solutions do not match:\n%s", cmp.Diff(want.GetSolutions(), got.GetSolutions(), opts...))
+			}
+
+			if len(want.GetTests()) != len(got.GetTests()) {
+				t.Fatalf("Number of tests does not match: want %v, got %v", len(want.GetTests()), len(got.GetTests()))
+			}
+
+			for i := range want.GetTests() {
+				if want.GetTests()[i].GetInputUrl() != got.GetTests()[i].GetInputUrl() || want.GetTests()[i].GetScore() != got.GetTests()[i].GetScore() {
+					t.Errorf("Test %v does not match", i+1)
+				}
+			}
+		})
+	}
+}
+
+// droppedContentFS is a problem package with content which can not be imported
+func droppedContentFS() fstest.MapFS {
+	return fstest.MapFS{
+		"problem.xml": {Data: []byte(`<problem>
+  <statements>
+    <statement language="english" path="statements/.html/english/problem.html" type="text/html"/>
+    <statement language="english" path="statements/english/problem.tex" type="application/x-tex"/>
+    <statement language="french" path="statements/.pdf/french/problem.pdf" type="application/pdf"/>
+    <statement language="klingon" path="statements/klingon/problem.tex" type="application/x-tex"/>
+  </statements>
+  <files>
+    <executables>
+      <executable><source path="files/gen.hs" type="haskell"/></executable>
+    </executables>
+  </files>
+  <assets>
+    <checker name="std::ncmp.cpp" type="testlib"/>
+    <validators>
+      <validator><source path="files/validator.hs" type="haskell"/></validator>
+    </validators>
+    <solutions>
+      <solution tag="unknown"><source path="solutions/unknown.cpp" type="cpp.g++17"/></solution>
+      <solution tag="accepted"><source path="solutions/accepted.hs" type="haskell"/></solution>
+    </solutions>
+  </assets>
+  <tags>
+    <tag value="eolymp_tl=fast"/>
+  </tags>
+</problem>`)},
+		"statements/english/problem-properties.json": {Data: []byte(`{"name": "A + B", "legend": "Sum two numbers"}`)},
+	}
+}
+
+func TestProblemLoader_ImportWarnings(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t})
+
+	_, report, err := loader.SnapshotFS(ctx, droppedContentFS())
+	if err != nil {
+		t.Fatal("Problem snapshot has failed:", err)
+	}
+
+	want := []*ImportWarning{
+		{Code: WarningUnmappedRuntime, Severity: SeverityWarning, Element: "assets/validators/validator/source[@path='files/validator.hs']"},
+		{Code: WarningUnsupportedFormat, Severity: SeverityInfo, Element: "statements/statement[@path='statements/.html/english/problem.html']"},
+		{Code: WarningUnsupportedFormat, Severity: SeverityWarning, Element: "statements/statement[@path='statements/.pdf/french/problem.pdf']"},
+		{Code: WarningUnsupportedLanguage, Severity: SeverityWarning, Element: "statements/statement[@path='statements/klingon/problem.tex']"},
+		{Code: WarningInvalidTag, Severity: SeverityWarning, Element: "tags/tag[@value='eolymp_tl=fast']"},
+		{Code: WarningUnmappedTag, Severity: SeverityWarning, Element: "assets/solutions/solution/source[@path='solutions/unknown.cpp']"},
+		{Code: WarningUnmappedRuntime, Severity: SeverityWarning, Element: "assets/solutions/solution/source[@path='solutions/accepted.hs']"},
+		{Code: WarningUnmappedRuntime, Severity: SeverityWarning, Element: "files/executables/executable/source[@path='files/gen.hs']"},
+	}
+
+	var got []*ImportWarning
+	for _, warning := range report.Warnings {
+		if warning.Message == "" {
+			t.Errorf("Warning %v for %v must have a message", warning.Code, warning.Element)
+		}
+
+		got = append(got, &ImportWarning{Code: warning.Code, Severity: warning.Severity, Element: warning.Element})
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("Import warnings do not match:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestProblemLoader_StrictImport(t *testing.T) {
+	ctx := context.Background()
+	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t}, UseStrictImport())
+
+	t.Run("package without dropped content", func(t *testing.T) {
+		if _, _, err := loader.Snapshot(ctx, ".testdata/20-checker-tests"); err != nil {
+			t.Fatal("Problem snapshot has failed:", err)
+		}
+	})
+
+	t.Run("package with dropped content", func(t *testing.T) {
+		_, _, err := loader.SnapshotFS(ctx, droppedContentFS())
+
+		var strictErr *StrictError
+		if !errors.As(err, &strictErr) {
+			t.Fatalf("Snapshot must fail with StrictError, got %v instead", err)
+		}
+
+		// informational warning about HTML statement is not a violation, since LaTeX statement is imported
+		if want, got := 7, len(strictErr.Warnings); want != got {
+			t.Errorf("Number of violations does not match: want %v, got %v", want, got)
+		}
+
+		for _, element := range []string{"statements/klingon/problem.tex", "files/gen.hs", "files/validator.hs"} {
+			if !strings.Contains(err.Error(), element) {
+				t.Errorf("Error message must mention %v: %v", element, err)
+			}
+		}
+	})
+
+	t.Run("package with dropped validator", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"problem.xml": {Data: []byte(`<problem>
+  <assets>
+    <checker name="std::ncmp.cpp" type="testlib"/>
+    <validators>
+      <validator><source path="files/validator.hs" type="haskell"/></validator>
+    </validators>
+  </assets>
+</problem>`)},
+		}
+
+		var strictErr *StrictError
+		if _, _, err := loader.SnapshotFS(ctx, fsys); !errors.As(err, &strictErr) {
+			t.Fatalf("Snapshot must fail with StrictError, got %v instead", err)
+		}
+
+		if len(strictErr.Warnings) != 1 || strictErr.Warnings[0].Code != WarningUnmappedRuntime {
+			t.Errorf("Violation must be reported for the validator, got %+v", strictErr.Warnings)
+		}
+	})
+}
+
+func TestProblemLoader_Workspace(t *testing.T) {
+	ctx := context.Background()
+
+	root := t.TempDir()
+
+	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t}, UseWorkspace(root), UseKeepFailedWorkspace())
+
+	t.Run("remove workspace after import", func(t *testing.T) {
+		_, _, err := loader.fetch(ctx, func(ctx context.Context, path string) error {
+			return os.WriteFile(filepath.Join(path, "problem.zip"), zipDir(t, ".testdata/01-topics"), 0600)
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if entries, _ := os.ReadDir(root); len(entries) != 0 {
+			t.Errorf("Workspace must be removed after successful import, found %v", entries)
+		}
+	})
+
+	t.Run("keep workspace on failure", func(t *testing.T) {
+		_, _, err := loader.fetch(ctx, func(ctx context.Context, path string) error {
+			return os.WriteFile(filepath.Join(path, "problem.zip"), []byte("not a zip"), 0600)
+		})
+
+		var workspace *WorkspaceError
+		if !errors.As(err, &workspace) {
+			t.Fatalf("Fetch must fail with WorkspaceError, got %v instead", err)
+		}
+
+		if want, got := root, filepath.Dir(workspace.Path); want != got {
+			t.Errorf("Workspace must be created in %v, got %v", want, got)
+		}
+
+		info, serr := os.Stat(workspace.Path)
+		if serr != nil {
+			t.Fatalf("Workspace must be kept: %v", serr)
+		}
+
+		if want, got := os.FileMode(0700), info.Mode().Perm(); want != got {
+			t.Errorf("Workspace permissions do not match: want %v, got %v", want, got)
+		}
+
+		if !strings.Contains(err.Error(), workspace.Path) {
+			t.Errorf("Error message must contain workspace location, got %v", err)
+		}
+	})
+}
+
 func TestProblemLoader_Snapshot(t *testing.T) {
 	ctx := context.Background()
 	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t})
 
 	t.Run("import topics", func(t *testing.T) {
-		snap, err := loader.Snapshot(ctx, ".testdata/01-topics")
+		snap, _, err := loader.Snapshot(ctx, ".testdata/01-topics")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -92,7 +554,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 	})
 
 	t.Run("import statements", func(t *testing.T) {
-		snap, err := loader.Snapshot(ctx, ".testdata/02-statements")
+		snap, _, err := loader.Snapshot(ctx, ".testdata/02-statements")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -111,7 +573,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 	})
 
 	t.Run("import test points from problem.xml", func(t *testing.T) {
-		snap, err := loader.Snapshot(ctx, ".testdata/03-test-scoring-with-points")
+		snap, _, err := loader.Snapshot(ctx, ".testdata/03-test-scoring-with-points")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -138,7 +600,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 	})
 
 	t.Run("set 100 points evenly if there are none in problem.xml", func(t *testing.T) {
-		snap, err := loader.Snapshot(ctx, ".testdata/04-test-scoring-without-points")
+		snap, _, err := loader.Snapshot(ctx, ".testdata/04-test-scoring-without-points")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -165,7 +627,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 	})
 
 	t.Run("import tutorials", func(t *testing.T) {
-		snap, err := loader.Snapshot(ctx, ".testdata/05-tutorials")
+		snap, _, err := loader.Snapshot(ctx, ".testdata/05-tutorials")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -182,7 +644,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 	})
 
 	t.Run("import solutions", func(t *testing.T) {
-		snap, err := loader.Snapshot(ctx, ".testdata/06-solutions")
+		snap, _, err := loader.Snapshot(ctx, ".testdata/06-solutions")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -207,7 +669,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 	})
 
 	t.Run("import problem with images", func(t *testing.T) {
-		snap, err := loader.Snapshot(ctx, ".testdata/07-images-in-text")
+		snap, _, err := loader.Snapshot(ctx, ".testdata/07-images-in-text")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -227,7 +689,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 
 	// use `eolymp_tl=` and `eolymp_ml=` tags to override time and memory limits
 	t.Run("import problem with custom time and memory limits", func(t *testing.T) {
-		snap, err := loader.Snapshot(ctx, ".testdata/08-custom-limit")
+		snap, _, err := loader.Snapshot(ctx, ".testdata/08-custom-limit")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -247,7 +709,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 	})
 
 	t.Run("import editorial with images", func(t *testing.T) {
-		snap, err := loader.Snapshot(ctx, ".testdata/09-images-in-tutorial")
+		snap, _, err := loader.Snapshot(ctx, ".testdata/09-images-in-tutorial")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -264,7 +726,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 	})
 
 	t.Run("import run count", func(t *testing.T) {
-		snap, err := loader.Snapshot(ctx, ".testdata/10-run-count")
+		snap, _, err := loader.Snapshot(ctx, ".testdata/10-run-count")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -279,7 +741,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 
 	// importing generated tests without actual files
 	t.Run("import tests generator", func(t *testing.T) {
-		got, err := loader.Snapshot(ctx, ".testdata/11-tests-generator")
+		got, _, err := loader.Snapshot(ctx, ".testdata/11-tests-generator")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -316,7 +778,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 
 	// importing generated tests with pre-generated files (i.e. full windows package)
 	t.Run("import pre generated tests", func(t *testing.T) {
-		got, err := loader.Snapshot(ctx, ".testdata/12-tests-generator-pregenerated")
+		got, _, err := loader.Snapshot(ctx, ".testdata/12-tests-generator-pregenerated")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -345,7 +807,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 	})
 
 	t.Run("import templates", func(t *testing.T) {
-		got, err := loader.Snapshot(ctx, ".testdata/13-templates")
+		got, _, err := loader.Snapshot(ctx, ".testdata/13-templates")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -368,7 +830,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 	})
 
 	t.Run("custom sample", func(t *testing.T) {
-		got, err := loader.Snapshot(ctx, ".testdata/14-custom-sample")
+		got, _, err := loader.Snapshot(ctx, ".testdata/14-custom-sample")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -409,7 +871,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 	})
 
 	t.Run("validator", func(t *testing.T) {
-		got, err := loader.Snapshot(ctx, ".testdata/15-validator")
+		got, _, err := loader.Snapshot(ctx, ".testdata/15-validator")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -427,7 +889,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 	})
 
 	t.Run("interactive-second-run", func(t *testing.T) {
-		got, err := loader.Snapshot(ctx, ".testdata/16-interactive-second-run")
+		got, _, err := loader.Snapshot(ctx, ".testdata/16-interactive-second-run")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -454,7 +916,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 	})
 
 	t.Run("attachments", func(t *testing.T) {
-		got, err := loader.Snapshot(ctx, ".testdata/17-attachments")
+		got, _, err := loader.Snapshot(ctx, ".testdata/17-attachments")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -472,7 +934,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 	})
 
 	t.Run("import templates with files", func(t *testing.T) {
-		got, err := loader.Snapshot(ctx, ".testdata/18-template-with-files")
+		got, _, err := loader.Snapshot(ctx, ".testdata/18-template-with-files")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -495,7 +957,7 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 	})
 
 	t.Run("import generator with files", func(t *testing.T) {
-		got, err := loader.Snapshot(ctx, ".testdata/19-generator-with-files")
+		got, _, err := loader.Snapshot(ctx, ".testdata/19-generator-with-files")
 		if err != nil {
 			t.Fatal("Problem snapshot has failed:", err)
 		}
@@ -511,4 +973,25 @@ func TestProblemLoader_Snapshot(t *testing.T) {
 		}
 	})
 
+	t.Run("checker and validator tests", func(t *testing.T) {
+		_, got, err := loader.Snapshot(ctx, ".testdata/20-checker-tests")
+		if err != nil {
+			t.Fatal("Problem snapshot has failed:", err)
+		}
+
+		want := &ImportReport{
+			CheckerTests: []*ImportedCheckerTest{
+				{Index: 1, InputURL: "https://eolympusercontent.com/file/01.f303b7d2f2b87f9e16df05e2bca7c409", OutputURL: "https://eolympusercontent.com/file/01.o.6d7fce9fee471194aa8b5b6e47267f03", AnswerURL: "https://eolympusercontent.com/file/01.a.6d7fce9fee471194aa8b5b6e47267f03", Verdict: "OK"},
+				{Index: 2, InputURL: "https://eolympusercontent.com/file/02.f303b7d2f2b87f9e16df05e2bca7c409", OutputURL: "https://eolympusercontent.com/file/02.o.48a24b70a0b376535542b996af517398", AnswerURL: "https://eolympusercontent.com/file/02.a.6d7fce9fee471194aa8b5b6e47267f03", Verdict: "WRONG_ANSWER"},
+			},
+			ValidatorTests: []*ImportedValidatorTest{
+				{Validator: "files/val.cpp", Index: 1, InputURL: "https://eolympusercontent.com/file/01.1dcca23355272056f04fe8bf20edfce0", Verdict: "VALID"},
+				{Validator: "files/val.cpp", Index: 2, InputURL: "https://eolympusercontent.com/file/02.166d77ac1b46a1ec38aa35ab7e628ab5", Verdict: "INVALID"},
+			},
+		}
+
+		if !cmp.Equal(want, got, opts...) {
+			t.Fatalf("Import report does not match:\n%s", cmp.Diff(want, got, opts...))
+		}
+	})
 }
@@ -0,0 +1,174 @@
+package polygon
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// ratioThreshold is the minimal size of the entry for compression ratio to be checked, small files might be compressed
+// very well without being dangerous
+const ratioThreshold = 1 << 20
+
+// UnpackLimits protect loader from archives which would exhaust disk space when unpacked (aka zip bombs), zero value of
+// any limit disables it.
+type UnpackLimits struct {
+	MaxTotalSize int64   // maximum total size of unpacked files in bytes
+	MaxFileSize  int64   // maximum size of a single unpacked file in bytes
+	MaxEntries   int     // maximum number of entries in the archive
+	MaxRatio     float64 // maximum compression ratio (unpacked size divided by compressed size) of a single file
+}
+
+// defaultUnpackLimits protect workers from zip bombs, they are far above the size of real packages. Compression ratio
+// is not limited, because large tests made of repeated values compress as well as zip bombs do.
+var defaultUnpackLimits = UnpackLimits{MaxTotalSize: 8 << 30, MaxFileSize: 2 << 30, MaxEntries: 100000}
+
+// UnpackError is returned when problem archive exceeds unpack limits or contains unsafe entries.
+type UnpackError struct {
+	Entry  string // name of the offending entry
+	Reason string
+}
+
+func (e *UnpackError) Error() string {
+	return fmt.Sprintf("archive entry %#v is rejected: %v", e.Entry, e.Reason)
+}
+
+// checkEntries makes sure archive does not have too many entries
+func (l UnpackLimits) checkEntries(files []*zip.File) error {
+	if l.MaxEntries > 0 && len(files) > l.MaxEntries {
+		return &UnpackError{Entry: files[l.MaxEntries].Name, Reason: fmt.Sprintf("archive has more than %v entries", l.MaxEntries)}
+	}
+
+	return nil
+}
+
+// check entry header before unpacking it, names are used to find duplicates
+func (l UnpackLimits) check(file *zip.File, names map[string]bool, name string) error {
+	if mode := file.Mode(); !mode.IsDir() && !mode.IsRegular() {
+		return &UnpackError{Entry: file.Name, Reason: "entry is not a regular file or directory (e.g. a symbolic link)"}
+	}
+
+	if file.Mode().IsDir() {
+		return nil
+	}
+
+	if names[name] {
+		return &UnpackError{Entry: file.Name, Reason: "archive has more than one entry with this name"}
+	}
+
+	names[name] = true
+
+	if l.MaxFileSize > 0 && file.UncompressedSize64 > uint64(l.MaxFileSize) {
+		return &UnpackError{Entry: file.Name, Reason: fmt.Sprintf("file is larger than %v bytes", l.MaxFileSize)}
+	}
+
+	if l.MaxRatio > 0 && file.UncompressedSize64 > ratioThreshold &&
+		float64(file.UncompressedSize64) > l.MaxRatio*float64(max(file.CompressedSize64, 1)) {
+		return &UnpackError{Entry: file.Name, Reason: fmt.Sprintf("compression ratio exceeds %v", l.MaxRatio)}
+	}
+
+	return nil
+}
+
+// limitedEntry copies entry content enforcing limits, sizes declared in zip headers might be forged, so actual number
+// of written bytes is checked
+type limitedEntry struct {
+	file   *zip.File
+	limits UnpackLimits
+	total  *int64 // total number of unpacked bytes in all entries
+	size   int64
+}
+
+func (e *limitedEntry) Write(data []byte) (int, error) {
+	e.size += int64(len(data))
+	*e.total += int64(len(data))
+
+	switch {
+	case e.limits.MaxFileSize > 0 && e.size > e.limits.MaxFileSize:
+		return 0, &UnpackError{Entry: e.file.Name, Reason: fmt.Sprintf("file is larger than %v bytes", e.limits.MaxFileSize)}
+	case e.limits.MaxTotalSize > 0 && *e.total > e.limits.MaxTotalSize:
+		return 0, &UnpackError{Entry: e.file.Name, Reason: fmt.Sprintf("unpacked archive is larger than %v bytes", e.limits.MaxTotalSize)}
+	case e.limits.MaxRatio > 0 && e.size > ratioThreshold && float64(e.size) > e.limits.MaxRatio*float64(max(e.file.CompressedSize64, 1)):
+		return 0, &UnpackError{Entry: e.file.Name, Reason: fmt.Sprintf("compression ratio exceeds %v", e.limits.MaxRatio)}
+	}
+
+	return len(data), nil
+}
+
+// copy entry content from src to dst
+func (e *limitedEntry) copy(dst io.Writer, src io.Reader) error {
+	_, err := io.Copy(io.MultiWriter(e, dst), src)
+	return err
+}
+
+// limitedFS reads problem archive without unpacking it, entries are checked against the same limits as unpacked files
+type limitedFS struct {
+	reader *zip.Reader
+	limits UnpackLimits
+	files  map[string]*zip.File // regular files by their fs.FS name
+}
+
+// limitArchive checks archive entries and wraps archive, so reads from it are limited
+func (l UnpackLimits) limitArchive(reader *zip.Reader) (*limitedFS, error) {
+	if err := l.checkEntries(reader.File); err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	files := map[string]*zip.File{}
+	total := uint64(0)
+
+	for _, file := range reader.File {
+		name := fsName(file.Name)
+
+		if err := l.check(file, names, name); err != nil {
+			return nil, err
+		}
+
+		if file.Mode().IsDir() {
+			continue
+		}
+
+		total += file.UncompressedSize64
+		if l.MaxTotalSize > 0 && total > uint64(l.MaxTotalSize) {
+			return nil, &UnpackError{Entry: file.Name, Reason: fmt.Sprintf("unpacked archive is larger than %v bytes", l.MaxTotalSize)}
+		}
+
+		files[name] = file
+	}
+
+	return &limitedFS{reader: reader, limits: l, files: files}, nil
+}
+
+func (f *limitedFS) Open(name string) (fs.File, error) {
+	file, err := f.reader.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, ok := f.files[name]
+	if !ok {
+		return file, nil
+	}
+
+	// each read is limited on its own, the same file might be read a few times (e.g. to hash and upload it)
+	return &limitedFile{File: file, entry: &limitedEntry{file: entry, limits: f.limits, total: new(int64)}}, nil
+}
+
+// limitedFile is a file from the archive which fails if its content exceeds limits
+type limitedFile struct {
+	fs.File
+	entry *limitedEntry
+}
+
+func (f *limitedFile) Read(data []byte) (int, error) {
+	n, err := f.File.Read(data)
+	if n > 0 {
+		if _, err := f.entry.Write(data[:n]); err != nil {
+			return 0, err
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,194 @@
+package polygon
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// zipEntry describes entry for writeZip
+type zipEntry struct {
+	name string
+	data string
+	mode os.FileMode
+}
+
+// writeZip creates problem.zip with given entries in a new workspace directory
+func writeZip(t *testing.T, entries ...zipEntry) string {
+	path := t.TempDir()
+
+	file, err := os.Create(filepath.Join(path, "problem.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	writer := zip.NewWriter(file)
+
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate}
+		if entry.mode != 0 {
+			header.SetMode(entry.mode)
+		}
+
+		w, err := writer.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := w.Write([]byte(entry.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// noise generates incompressible data
+func noise(size int) string {
+	gen := rand.New(rand.NewPCG(1, 2))
+
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(gen.Uint32())
+	}
+
+	return string(data)
+}
+
+func TestProblemLoader_UnpackLimits(t *testing.T) {
+	ctx := context.Background()
+
+	limits := UnpackLimits{MaxTotalSize: 5 << 20, MaxFileSize: 3 << 20, MaxEntries: 5, MaxRatio: 100}
+
+	tests := []struct {
+		name    string
+		entries []zipEntry
+		want    string // offending entry, empty if archive is valid
+	}{
+		{
+			name:    "valid archive",
+			entries: []zipEntry{{name: "problem.xml", data: "<problem/>"}, {name: "tests/", mode: os.ModeDir | 0755}, {name: "tests/01", data: "1 2"}},
+		},
+		{
+			name:    "too many entries",
+			entries: []zipEntry{{name: "1"}, {name: "2"}, {name: "3"}, {name: "4"}, {name: "5"}, {name: "6"}},
+			want:    "6",
+		},
+		{
+			name:    "large file",
+			entries: []zipEntry{{name: "tests/01", data: noise(4 << 20)}},
+			want:    "tests/01",
+		},
+		{
+			name: "large archive",
+			entries: []zipEntry{
+				{name: "tests/01", data: noise(2 << 20)},
+				{name: "tests/02", data: noise(2 << 20)},
+				{name: "tests/03", data: noise(2 << 20)},
+			},
+			want: "tests/03",
+		},
+		{
+			name:    "compression ratio",
+			entries: []zipEntry{{name: "tests/01", data: strings.Repeat("0", 2<<20)}},
+			want:    "tests/01",
+		},
+		{
+			name:    "symbolic link",
+			entries: []zipEntry{{name: "passwd", data: "/etc/passwd", mode: os.ModeSymlink | 0777}},
+			want:    "passwd",
+		},
+		{
+			name:    "duplicate name",
+			entries: []zipEntry{{name: "tests/01", data: "1"}, {name: "./tests/01", data: "2"}},
+			want:    "./tests/01",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeZip(t, tc.entries...)
+
+			check := func(t *testing.T, err error) {
+				if tc.want == "" {
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					return
+				}
+
+				var unpackErr *UnpackError
+				if !errors.As(err, &unpackErr) {
+					t.Fatalf("Archive must be rejected with UnpackError, got %v instead", err)
+				}
+
+				if unpackErr.Entry != tc.want {
+					t.Errorf("Offending entry does not match: want %v, got %v", tc.want, unpackErr.Entry)
+				}
+			}
+
+			t.Run("unpack", func(t *testing.T) {
+				loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t}, UseUnpackLimits(limits))
+				check(t, loader.unpack(ctx, path))
+			})
+
+			t.Run("direct", func(t *testing.T) {
+				check(t, readArchive(t, limits, path))
+			})
+		})
+	}
+}
+
+// readArchive reads every file from problem.zip without unpacking it, like loader does with UseDirectArchive
+func readArchive(t *testing.T, limits UnpackLimits, path string) error {
+	reader, err := zip.OpenReader(filepath.Join(path, "problem.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer reader.Close()
+
+	fsys, err := limits.limitArchive(&reader.Reader)
+	if err != nil {
+		return err
+	}
+
+	return fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		_, err = fs.ReadFile(fsys, name)
+		return err
+	})
+}
+
+func TestProblemLoader_DefaultUnpackLimits(t *testing.T) {
+	ctx := context.Background()
+
+	entries := make([]zipEntry, defaultUnpackLimits.MaxEntries+1)
+	for i := range entries {
+		entries[i] = zipEntry{name: fmt.Sprintf("tests/%06d", i)}
+	}
+
+	loader := NewProblemLoader(&assetMock{}, &loggerMock{t: t})
+
+	var unpackErr *UnpackError
+	if err := loader.unpack(ctx, writeZip(t, entries...)); !errors.As(err, &unpackErr) {
+		t.Fatalf("Unpack must fail with UnpackError by default, got %v instead", err)
+	}
+}
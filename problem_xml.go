@@ -26,6 +26,24 @@ func (s *Specification) Tagged(tag string) bool {
 	return false
 }
 
+func (s *Specification) HasLatexStatement(language string) bool {
+	for _, statement := range s.Statements {
+		if statement.Language == language && statement.Type == "application/x-tex" {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Specification) HasLatexTutorial(language string) bool {
+	for _, tutorial := range s.Tutorials {
+		if tutorial.Language == language && tutorial.Type == "application/x-tex" {
+			return true
+		}
+	}
+	return false
+}
+
 type SpecificationName struct {
 	Language string `xml:"language,attr"`
 	Value    string `xml:"value,attr"`
@@ -121,17 +139,47 @@ type SpecificationDependency struct {
 }
 
 type SpecificationChecker struct {
-	Name     string                `xml:"name,attr"`
-	Type     string                `xml:"type,attr"`
-	Sources  []SpecificationSource `xml:"source"`
-	Binaries []SpecificationBinary `xml:"binary"`
+	Name     string                      `xml:"name,attr"`
+	Type     string                      `xml:"type,attr"`
+	Sources  []SpecificationSource       `xml:"source"`
+	Binaries []SpecificationBinary       `xml:"binary"`
+	Testset  SpecificationCheckerTestset `xml:"testset"`
+}
+
+// SpecificationCheckerTestset describes tests used to verify checker, each test has input, participant's output,
+// jury answer and expected checker verdict.
+type SpecificationCheckerTestset struct {
+	TestCount         int                        `xml:"test-count"`
+	InputPathPattern  string                     `xml:"input-path-pattern"`
+	OutputPathPattern string                     `xml:"output-path-pattern"`
+	AnswerPathPattern string                     `xml:"answer-path-pattern"`
+	Tests             []SpecificationVerdictTest `xml:"tests>test"`
 }
 
 type SpecificationValidator struct {
-	Name     string                `xml:"name,attr"`
-	Type     string                `xml:"type,attr"`
-	Sources  []SpecificationSource `xml:"source"`
-	Binaries []SpecificationBinary `xml:"binary"`
+	Name     string                        `xml:"name,attr"`
+	Type     string                        `xml:"type,attr"`
+	Sources  []SpecificationSource         `xml:"source"`
+	Binaries []SpecificationBinary         `xml:"binary"`
+	Testset  SpecificationValidatorTestset `xml:"testset"`
+}
+
+// SpecificationValidatorTestset describes tests used to verify validator, each test has input and expected verdict.
+func (v SpecificationValidator) Identity() string {
+	if v.Name != "" || len(v.Sources) == 0 {
+		return v.Name
+	}
+	return v.Sources[0].Path
+}
+
+type SpecificationValidatorTestset struct {
+	TestCount        int                        `xml:"test-count"`
+	InputPathPattern string                     `xml:"input-path-pattern"`
+	Tests            []SpecificationVerdictTest `xml:"tests>test"`
+}
+
+type SpecificationVerdictTest struct {
+	Verdict string `xml:"verdict,attr"` // ok, wrong-answer, presentation-error, crashed for checker; valid, invalid for validator
 }
 
 type SpecificationInteractor struct {
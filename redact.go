@@ -0,0 +1,125 @@
+package polygon
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const redacted = "xxxxx"
+
+// credentialPatterns match credentials in URLs and HTTP headers
+var credentialPatterns = []struct {
+	pattern *regexp.Regexp
+	replace string
+}{
+	{pattern: regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)[^/?#\s"'@]*@`), replace: "${1}" + redacted + "@"},
+	{pattern: regexp.MustCompile(`(?i)((?:apiKey|apiSig|apiSecret|login|password|token)=)[^&\s"'#]+`), replace: "${1}" + redacted},
+	{pattern: regexp.MustCompile(`(?i)(Bearer\s+)[^\s"']+`), replace: "${1}" + redacted},
+}
+
+// redact removes credentials from the text, secrets are replaced wherever they appear in the text
+func redact(text string, secrets ...string) string {
+	for _, secret := range secrets {
+		// very short values can not be replaced without mangling the text, they are covered by patterns
+		if len(secret) >= 4 {
+			text = strings.ReplaceAll(text, secret, redacted)
+		}
+	}
+
+	for _, cp := range credentialPatterns {
+		text = cp.pattern.ReplaceAllString(text, cp.replace)
+	}
+
+	return text
+}
+
+// linkSecrets returns username and password from the link, the link is not required to be a valid URL
+func linkSecrets(link string) []string {
+	username, password, ok := linkUserinfo(link)
+	if !ok {
+		return nil
+	}
+
+	// values may be percent-encoded in the link, but decoded or quoted in error messages
+	secrets := []string{username, password}
+	for _, value := range []string{username, password} {
+		if decoded, err := url.PathUnescape(value); err == nil && decoded != value {
+			secrets = append(secrets, decoded)
+			value = decoded
+		}
+
+		if quoted := strconv.Quote(value); quoted[1:len(quoted)-1] != value {
+			secrets = append(secrets, quoted[1:len(quoted)-1])
+		}
+	}
+
+	return secrets
+}
+
+// linkUserinfo extracts username and password from the link as they are written in it, userinfo ends at the last "@"
+// of the authority, which ends with the first "/", "?" or "#". If the link is not a valid URL (e.g. password contains
+// unescaped "/"), authority is assumed to end with query or fragment instead.
+func linkUserinfo(link string) (string, string, bool) {
+	start := strings.Index(link, "://")
+	if start < 0 {
+		return "", "", false
+	}
+
+	delimiters := "/?#"
+	if _, err := url.Parse(link); err != nil {
+		delimiters = "?#"
+	}
+
+	authority := link[start+3:]
+	if end := strings.IndexAny(authority, delimiters); end >= 0 {
+		authority = authority[:end]
+	}
+
+	end := strings.LastIndex(authority, "@")
+	if end < 0 {
+		return "", "", false
+	}
+
+	username, password, _ := strings.Cut(authority[:end], ":")
+
+	return username, password, true
+}
+
+// redactedError hides credentials in the message of the wrapped error
+type redactedError struct {
+	err     error
+	secrets []string
+}
+
+// redactError wraps error, so its message does not contain credentials
+func redactError(err error, secrets ...string) error {
+	if err == nil {
+		return nil
+	}
+
+	return &redactedError{err: err, secrets: secrets}
+}
+
+func (e *redactedError) Error() string {
+	return redact(e.err.Error(), e.secrets...)
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactedLogger removes credentials from log messages
+type redactedLogger struct {
+	log logger
+}
+
+func (l *redactedLogger) Printf(format string, args ...any) {
+	l.log.Printf("%s", redact(fmt.Sprintf(format, args...)))
+}
+
+func (l *redactedLogger) Errorf(format string, args ...any) {
+	l.log.Errorf("%s", redact(fmt.Sprintf(format, args...)))
+}
@@ -1,20 +1,48 @@
 package polygon
 
 import (
-	"crypto/sha512"
+	"crypto/rand"
+	"crypto/subtle"
 	"fmt"
-	"math/rand"
+	"math/big"
 	"net/url"
-	"time"
-)
 
-var rnd = rand.New(rand.NewSource(time.Now().Unix()))
+	"github.com/eolymp/go-polygon/internal/apisig"
+)
 
+// Signature signs API request with a random salt, params must include all request parameters except apiSig.
 func Signature(method string, secret string, params url.Values) string {
-	salt := fmt.Sprint(100000 + rnd.Int()%899999)
+	return sign(Salt(), method, secret, params)
+}
+
+// VerifySignature checks apiSig parameter of the incoming API request. It does not check request time, caller should
+// reject requests with time parameter too far from the current time to prevent replay.
+func VerifySignature(method string, secret string, params url.Values) bool {
+	sig := params.Get("apiSig")
+	if len(sig) < 6 {
+		return false
+	}
+
+	query := url.Values{}
+	for k, v := range params {
+		if k != "apiSig" {
+			query[k] = v
+		}
+	}
 
-	hash := sha512.New()
-	hash.Write([]byte(salt + "/" + method + "?" + params.Encode() + "#" + secret))
+	return subtle.ConstantTimeCompare([]byte(sig), []byte(sign(sig[:6], method, secret, query))) == 1
+}
+
+// Salt generates random 6 digit salt for the request signature.
+func Salt() string {
+	n, err := rand.Int(rand.Reader, big.NewInt(900000))
+	if err != nil {
+		panic(fmt.Errorf("unable to generate random salt: %w", err))
+	}
+
+	return fmt.Sprint(100000 + n.Int64())
+}
 
-	return salt + fmt.Sprintf("%x", hash.Sum(nil))
+func sign(salt, method, secret string, params url.Values) string {
+	return apisig.Sign(salt, method, secret, params)
 }
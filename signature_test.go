@@ -0,0 +1,64 @@
+package polygon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type httpClientFunc func(req *http.Request) (*http.Response, error)
+
+func (f httpClientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestClient_DeterministicSignature(t *testing.T) {
+	ctx := context.Background()
+
+	var got string
+
+	poly := New("key", "secret",
+		UseClock(func() time.Time { return time.Unix(1700000000, 0) }),
+		UseSalt(func() string { return "123456" }),
+		UseHTTPClient(httpClientFunc(func(req *http.Request) (*http.Response, error) {
+			got = req.URL.String()
+			return nil, errors.New("offline")
+		})),
+	)
+
+	_, _ = poly.ProblemInfo(ctx, ProblemInfoInput{ProblemID: 123})
+
+	want := "https://polygon.codeforces.com/api/problem.info?apiKey=key&apiSig=123456" +
+		"5b7638526887c5e90e4635c6d5999a73e752bc926345a851853fed0cecb6da476411e7483f80e47fd4a2f1d8d57ba47cde77c84ea2ae1a83a0b9abb06bb46ac1" +
+		"&problemId=123&time=1700000000"
+
+	if want != got {
+		t.Errorf("Request URL does not match:\n want %v\n  got %v", want, got)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	params := url.Values{"apiKey": {"key"}, "problemId": {"123"}, "time": {"1700000000"}}
+	params.Set("apiSig", Signature("problem.info", "secret", params))
+
+	if !VerifySignature("problem.info", "secret", params) {
+		t.Errorf("Valid signature is rejected")
+	}
+
+	if VerifySignature("problem.info", "wrong-secret", params) {
+		t.Errorf("Signature made with different secret is accepted")
+	}
+
+	if VerifySignature("problem.packages", "secret", params) {
+		t.Errorf("Signature made for different method is accepted")
+	}
+
+	params.Set("problemId", "124")
+
+	if VerifySignature("problem.info", "secret", params) {
+		t.Errorf("Signature of tampered parameters is accepted")
+	}
+}
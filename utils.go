@@ -1,8 +1,22 @@
 package polygon
 
-import "os"
+import (
+	"io/fs"
+	"path"
+	"strings"
+)
 
-func fileExists(filename string) bool {
-	_, err := os.Stat(filename)
+func fileExists(fsys fs.FS, name string) bool {
+	_, err := fs.Stat(fsys, name)
 	return err == nil
 }
+
+// fsName joins path elements from problem.xml into a name valid for fs.FS, the name can not point outside the root
+func fsName(elem ...string) string {
+	name := strings.TrimPrefix(path.Clean("/"+path.Join(elem...)), "/")
+	if name == "" {
+		return "."
+	}
+
+	return name
+}